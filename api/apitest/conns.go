@@ -0,0 +1,149 @@
+package apitest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+var upgrader = websocket.Upgrader{
+	// The fake serves clients with arbitrary origins (api.Client sends the
+	// labctl CLI one).
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (s *Server) handleRequestPlayConn(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	play, ok := s.plays[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	if play.StateIs(api.StateDestroyed) {
+		writeError(w, http.StatusConflict, "play is destroyed")
+		return
+	}
+
+	if s.refuseConns {
+		writeError(w, http.StatusServiceUnavailable, "play connections are unavailable")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.PlayConnHandle{
+		URL: "ws" + strings.TrimPrefix(s.URL, "http") + "/conns/" + id,
+	})
+}
+
+// handlePlayConn streams the play's status and task updates to the client
+// until either side goes away.
+func (s *Server) handlePlayConn(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	_, ok := s.plays[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	// Subscribing before the handshake completes means that once the client's
+	// dial returns, no update can slip by unnoticed.
+	msgCh := s.subscribe(id)
+	defer s.unsubscribe(id, msgCh)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// The client never sends anything meaningful, but reading is how a closed
+	// connection gets noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case msg, ok := <-msgCh:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// DropConns abruptly closes all open play connections of the play - the way a
+// flaky network would, without a WebSocket close frame.
+func (s *Server) DropConns(playID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.conns[playID] {
+		delete(s.conns[playID], ch)
+		close(ch)
+	}
+}
+
+// RefuseConns makes new play connection requests fail with 503 (refuse=true)
+// until called again with refuse=false. Open connections aren't affected.
+func (s *Server) RefuseConns(refuse bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refuseConns = refuse
+}
+
+func (s *Server) subscribe(id string) chan api.PlayConnMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan api.PlayConnMessage, 1024)
+	if s.conns[id] == nil {
+		s.conns[id] = make(map[chan api.PlayConnMessage]struct{})
+	}
+	s.conns[id][ch] = struct{}{}
+
+	return ch
+}
+
+func (s *Server) unsubscribe(id string, ch chan api.PlayConnMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.conns[id][ch]; ok {
+		delete(s.conns[id], ch)
+		close(ch)
+	}
+}
+
+// broadcast must be called with s.mu held. Slow subscribers lose messages
+// rather than stalling the server - just like with a real flaky connection.
+func (s *Server) broadcast(id string, msg api.PlayConnMessage) {
+	for ch := range s.conns[id] {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
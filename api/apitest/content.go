@@ -0,0 +1,185 @@
+package apitest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/content"
+)
+
+// ContentFile returns the content of a file previously pushed to the fake.
+func (s *Server) ContentFile(kind content.ContentKind, name, file string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[kind.Plural()+"/"+name][file]
+	return data, ok
+}
+
+// PutContentFile stores a file as if it had been pushed by an author.
+func (s *Server) PutContentFile(kind content.ContentKind, name, file string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putFile(kind.Plural()+"/"+name, file, data)
+}
+
+func (s *Server) putFile(key, file string, data []byte) {
+	if s.files[key] == nil {
+		s.files[key] = make(map[string][]byte)
+	}
+	s.files[key][file] = data
+}
+
+// contentKey resolves the kind/name query parameters the content endpoints
+// share into the key files are stored under.
+func contentKey(r *http.Request) (string, bool) {
+	var kind content.ContentKind
+	if err := kind.Set(r.URL.Query().Get("kind")); err != nil {
+		return "", false
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		return "", false
+	}
+
+	return kind.Plural() + "/" + name, true
+}
+
+func (s *Server) handleListContentFiles(w http.ResponseWriter, r *http.Request) {
+	key, ok := contentKey(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "kind and name are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, slices.Sorted(maps.Keys(s.files[key])))
+}
+
+func (s *Server) handleListContentFilesV2(w http.ResponseWriter, r *http.Request) {
+	key, ok := contentKey(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "kind and name are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := []api.ContentFile{}
+	for _, path := range slices.Sorted(maps.Keys(s.files[key])) {
+		digest := md5.Sum(s.files[key][path])
+		files = append(files, api.ContentFile{Path: path, Digest: hex.EncodeToString(digest[:])})
+	}
+
+	writeJSON(w, http.StatusOK, files)
+}
+
+func (s *Server) handleRequestContentUpload(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+		File string `json:"file"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var kind content.ContentKind
+	if err := kind.Set(req.Kind); err != nil || req.Name == "" || req.File == "" {
+		writeError(w, http.StatusBadRequest, "kind, name and file are required")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"uploadUrl": s.URL + "/uploads/" + kind.Plural() + "/" + req.Name + "/" + req.File,
+	})
+}
+
+func (s *Server) handleUploadContentFile(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putFile(r.PathValue("kind")+"/"+r.PathValue("name"), r.PathValue("file"), data)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDownloadContentFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[r.PathValue("kinds")+"/"+r.PathValue("name")][r.PathValue("file")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleDeleteContentFile(w http.ResponseWriter, r *http.Request) {
+	key, ok := contentKey(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "kind and name are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := r.URL.Query().Get("file")
+	if _, ok := s.files[key][file]; !ok {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	delete(s.files[key], file)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePutContentMarkdown(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Kind    string `json:"kind"`
+		Name    string `json:"name"`
+		File    string `json:"file"`
+		Content string `json:"content"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var kind content.ContentKind
+	if err := kind.Set(req.Kind); err != nil || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "kind and name are required")
+		return
+	}
+
+	file := req.File
+	if file == "" {
+		file = "index.md"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putFile(kind.Plural()+"/"+req.Name, file, []byte(req.Content))
+	w.WriteHeader(http.StatusOK)
+}
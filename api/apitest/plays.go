@@ -0,0 +1,639 @@
+package apitest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const defaultMaxPlayTime = time.Hour
+
+// transitions is the subset of the server's PlayState machine the fake
+// enforces. Intermediate states are optional - a play can go straight from
+// CREATED to RUNNING - but a destroyed play is gone for good.
+var transitions = map[api.PlayState][]api.PlayState{
+	api.StateCreated:    {api.StateWarmingUp, api.StateStarting, api.StateRunning, api.StateDestroying, api.StateDestroyed, api.StateFailed},
+	api.StateWarmingUp:  {api.StateWarmedUp, api.StateStarting, api.StateRunning, api.StateDestroying, api.StateDestroyed, api.StateFailed},
+	api.StateWarmedUp:   {api.StateStarting, api.StateRunning, api.StateDestroying, api.StateDestroyed, api.StateFailed},
+	api.StateStarting:   {api.StateRunning, api.StateStopping, api.StateDestroying, api.StateFailed},
+	api.StateRunning:    {api.StateStopping, api.StateStopped, api.StateDestroying, api.StateDestroyed, api.StateFailed},
+	api.StateStopping:   {api.StateStopped, api.StateFailed},
+	api.StateStopped:    {api.StateStarting, api.StateRunning, api.StateDestroying, api.StateDestroyed},
+	api.StateDestroying: {api.StateDestroyed},
+	api.StateFailed:     {api.StateDestroying, api.StateDestroyed},
+}
+
+func canTransition(from, to api.PlayState) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Play returns a snapshot of the play with the given ID.
+func (s *Server) Play(id string) (*api.Play, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[id]
+	if !ok {
+		return nil, false
+	}
+	return s.snapshot(play), true
+}
+
+// Transition moves the play to the given state, going through any of the
+// intermediate states a real play would visit on the way.
+func (s *Server) Transition(id string, states ...api.PlayState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[id]
+	if !ok {
+		return api.ErrNotFound
+	}
+
+	for _, state := range states {
+		if err := s.transition(play, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTaskStatus updates a single task of the play and notifies any open play
+// connections, the same way the conductor reports task progress.
+func (s *Server) SetTaskStatus(id, task string, status api.PlayTaskStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[id]
+	if !ok {
+		return api.ErrNotFound
+	}
+
+	t, ok := play.Tasks[task]
+	if !ok {
+		return fmt.Errorf("task %q not found in play %s", task, id)
+	}
+
+	t.Status = status
+	t.Version++
+	play.Tasks[task] = t
+
+	s.broadcast(id, api.PlayConnMessage{Kind: "task", Task: t})
+	return nil
+}
+
+func (s *Server) transition(play *api.Play, to api.PlayState) error {
+	from := play.State()
+	if from == to {
+		return nil
+	}
+	if !canTransition(from, to) {
+		return fmt.Errorf("play %s can't go from %s to %s", play.ID, from, to)
+	}
+
+	play.Status.StateEvents = append(play.Status.StateEvents, api.StateEvent{State: to, At: now()})
+	play.UpdatedAt = now()
+	play.LastStateAt = now()
+
+	switch to {
+	case api.StateRunning:
+		s.setMachines(play, api.MachineStateRunning, "True")
+
+		// Init tasks run as soon as the machines are up. Regular tasks are
+		// left for the test to drive with SetTaskStatus.
+		for name, task := range play.Tasks {
+			if task.Init && task.Status != api.PlayTaskStatusCompleted && !s.opts.HoldInitTasks {
+				task.Status = api.PlayTaskStatusCompleted
+				task.Version++
+				play.Tasks[name] = task
+			}
+		}
+
+	case api.StateStarting:
+		s.setMachines(play, api.MachineStateStarting, "False")
+
+	case api.StateStopping:
+		s.setMachines(play, api.MachineStateStopping, "False")
+
+	case api.StateStopped, api.StateDestroyed, api.StateFailed:
+		s.setMachines(play, api.MachineStateStopped, "False")
+	}
+
+	s.broadcast(play.ID, api.PlayConnMessage{Kind: "status", Status: copyStatus(play.Status)})
+	for _, name := range slices.Sorted(maps.Keys(play.Tasks)) {
+		s.broadcast(play.ID, api.PlayConnMessage{Kind: "task", Task: play.Tasks[name]})
+	}
+
+	return nil
+}
+
+func (s *Server) setMachines(play *api.Play, state api.MachineState, ready string) {
+	machines := make([]api.MachineStatus, 0, len(play.Machines))
+	for _, m := range play.Machines {
+		machines = append(machines, api.MachineStatus{
+			Name:  m.Name,
+			State: state,
+			Conditions: []api.Condition{{
+				Name:             "Ready",
+				Status:           ready,
+				LastTransitionAt: time.Now().UTC(),
+			}},
+		})
+	}
+	play.Status.Machines = machines
+}
+
+// snapshot returns a deep enough copy of the play for it to be handed out
+// while the server keeps mutating the original.
+func (s *Server) snapshot(play *api.Play) *api.Play {
+	p := *play
+	p.Status = copyStatus(play.Status)
+	p.Machines = slices.Clone(play.Machines)
+	p.Tasks = maps.Clone(play.Tasks)
+
+	if p.IsActive() {
+		if started, err := time.Parse(time.RFC3339, p.CreatedAt); err == nil {
+			maxPlayTime, _ := time.ParseDuration(p.MaxPlayTime)
+			p.ExpiresIn = int(max(time.Until(started.Add(maxPlayTime)), 0).Milliseconds())
+		}
+	} else {
+		p.ExpiresIn = 0
+	}
+
+	return &p
+}
+
+func copyStatus(status *api.PlayStatus) *api.PlayStatus {
+	if status == nil {
+		return nil
+	}
+
+	st := *status
+	st.StateEvents = slices.Clone(status.StateEvents)
+	st.Machines = slices.Clone(status.Machines)
+	return &st
+}
+
+func (s *Server) handleListPlays(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	persistent := r.URL.Query().Get("persistent") == "true"
+
+	plays := []*api.Play{}
+	for _, id := range slices.Sorted(maps.Keys(s.plays)) {
+		play := s.plays[id]
+		if persistent != play.StateIs(api.StateStopped) {
+			continue
+		}
+		plays = append(plays, s.snapshot(play))
+	}
+
+	writeJSON(w, http.StatusOK, plays)
+}
+
+func (s *Server) handleCreatePlay(w http.ResponseWriter, r *http.Request) {
+	var req api.CreatePlayRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pg, ok := s.playgrounds[req.Playground]
+	if !ok {
+		writeError(w, http.StatusNotFound, "playground not found")
+		return
+	}
+
+	machines := pg.Machines
+	if len(req.Machines) > 0 {
+		machines = req.Machines
+	}
+	initTasks := pg.InitTasks
+	if len(req.InitTasks) > 0 {
+		initTasks = req.InitTasks
+	}
+
+	id := s.newID()
+	play := &api.Play{
+		ID:          id,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+		LastStateAt: now(),
+		MaxPlayTime: defaultMaxPlayTime.String(),
+		Status: &api.PlayStatus{
+			FactoryID:   "apitest",
+			StateEvents: []api.StateEvent{{State: api.StateCreated, At: now()}},
+		},
+		Playground: *pg,
+		PageURL:    s.URL + "/playgrounds/" + pg.Name + "/" + id,
+		Tasks:      make(map[string]api.PlayTask),
+	}
+
+	// The task details endpoint serves definitions from here, so keep the
+	// ones the play was actually started with.
+	play.Playground.InitTasks = initTasks
+
+	for _, m := range machines {
+		machine := api.Machine{Name: m.Name, Users: m.Users}
+		if m.Resources != nil {
+			machine.Resources = *m.Resources
+		}
+		play.Machines = append(play.Machines, machine)
+	}
+	s.setMachines(play, api.MachineStateCreated, "False")
+
+	for name, task := range initTasks {
+		play.Tasks[name] = api.PlayTask{
+			Name:   name,
+			Init:   task.Init,
+			Status: api.PlayTaskStatusCreated,
+		}
+	}
+
+	s.plays[id] = play
+	s.portForwards[id] = slices.Clone(pointers(pg.PortForwards))
+
+	if !s.opts.HoldCreated {
+		if err := s.transition(play, api.StateRunning); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.snapshot(play))
+}
+
+func (s *Server) handleGetPlay(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.snapshot(play))
+}
+
+func (s *Server) handleDeletePlay(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	if err := s.transition(play, api.StateDestroyed); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePlayAction(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Action             string `json:"action"`
+		Title              string `json:"title"`
+		Machine            string `json:"machine"`
+		Console            string `json:"console"`
+		MaxPlayTimeMinutes int    `json:"maxPlayTimeMinutes"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	var states []api.PlayState
+
+	switch req.Action {
+	case "set_title":
+		play.Title = req.Title
+
+	case "make_persistent":
+		// Persistence only affects what happens on stop, which the fake
+		// doesn't distinguish.
+
+	case "set_max_play_time":
+		if req.MaxPlayTimeMinutes < 1 {
+			writeError(w, http.StatusBadRequest, "maxPlayTimeMinutes must be positive")
+			return
+		}
+		play.MaxPlayTime = (time.Duration(req.MaxPlayTimeMinutes) * time.Minute).String()
+
+	case "stop":
+		states = []api.PlayState{api.StateStopping, api.StateStopped}
+
+	case "restart":
+		if play.StateIs(api.StateRunning) {
+			states = append(states, api.StateStopping, api.StateStopped)
+		}
+		states = append(states, api.StateStarting, api.StateRunning)
+
+	case "destroy":
+		states = []api.PlayState{api.StateDestroying, api.StateDestroyed}
+
+	case "machine.reboot", "machine.stop", "machine.restart":
+		if play.GetMachine(req.Machine) == nil {
+			writeError(w, http.StatusNotFound, "machine not found")
+			return
+		}
+
+	case "machine.console.list":
+		writeJSON(w, http.StatusOK, map[string]any{"consoles": []string{"ttyS0"}})
+		return
+
+	case "machine.console.read":
+		writeJSON(w, http.StatusOK, map[string]any{"content": ""})
+		return
+
+	default:
+		writeError(w, http.StatusBadRequest, "unknown action: "+req.Action)
+		return
+	}
+
+	for _, state := range states {
+		if err := s.transition(play, state); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.snapshot(play))
+}
+
+func (s *Server) handleGetPlayTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.plays[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	machines := r.URL.Query()["machine"]
+
+	tasks := []api.PlayTaskDetails{}
+	for _, name := range slices.Sorted(maps.Keys(play.Tasks)) {
+		task := play.Tasks[name]
+		def := play.Playground.InitTasks[name]
+
+		if len(machines) > 0 && !slices.Contains(machines, def.Machine) {
+			continue
+		}
+
+		tasks = append(tasks, api.PlayTaskDetails{
+			Name:           name,
+			Machine:        def.Machine,
+			Status:         task.Status,
+			Version:        task.Version,
+			Init:           task.Init,
+			Helper:         task.Helper,
+			Needs:          def.Needs,
+			Run:            def.Run,
+			User:           def.User,
+			TimeoutSeconds: def.TimeoutSeconds,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) handleStartTunnel(w http.ResponseWriter, r *http.Request) {
+	var req api.StartTunnelRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	play, ok := s.plays[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	machine := req.Machine
+	if machine == "" && len(play.Machines) > 0 {
+		machine = play.Machines[0].Name
+	}
+	if play.GetMachine(machine) == nil {
+		writeError(w, http.StatusNotFound, "machine not found")
+		return
+	}
+
+	// Same "not yet" answer the real tunnel endpoint gives for a machine
+	// that's still coming up.
+	if !play.MachineReady(machine) {
+		writeError(w, http.StatusServiceUnavailable, "machine is not ready yet")
+		return
+	}
+
+	resp := api.StartTunnelResponse{URL: s.URL + "/tunnels/" + id + "/" + machine}
+	if req.GenerateLoginURL {
+		resp.LoginURL = s.URL + "/tunnels/" + id + "/login"
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleTunnelLogin(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:  ".ixcondsess",
+		Value: "apitest-" + r.PathValue("id"),
+		Path:  "/",
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleListPortForwards(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.plays[id]; !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, append([]*api.PortForward{}, s.portForwards[id]...))
+}
+
+func (s *Server) handleAddPortForward(w http.ResponseWriter, r *http.Request) {
+	var pf api.PortForward
+	if err := readJSON(r, &pf); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.plays[id]; !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	s.portForwards[id] = append(s.portForwards[id], &pf)
+	writeJSON(w, http.StatusOK, &pf)
+}
+
+func (s *Server) handleRemovePortForward(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.plays[id]; !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index >= len(s.portForwards[id]) {
+		writeError(w, http.StatusNotFound, "port forward not found")
+		return
+	}
+
+	s.portForwards[id] = slices.Delete(s.portForwards[id], index, index+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListPorts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.plays[id]; !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, append([]*api.Port{}, s.ports[id]...))
+}
+
+func (s *Server) handleExposePort(w http.ResponseWriter, r *http.Request) {
+	var req api.ExposePortRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	play, ok := s.plays[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+	if play.GetMachine(req.Machine) == nil {
+		writeError(w, http.StatusNotFound, "machine not found")
+		return
+	}
+
+	access := req.Access
+	if access == "" {
+		access = api.AccessPrivate
+	}
+
+	portID := s.newID()
+	hostname := fmt.Sprintf("%s-%s-%d.apitest.local", id, req.Machine, req.Number)
+	port := &api.Port{
+		ID:          portID,
+		PlayID:      id,
+		Machine:     req.Machine,
+		Number:      req.Number,
+		Hostname:    hostname,
+		AccessMode:  access,
+		TLS:         req.TLS,
+		HostRewrite: req.HostRewrite,
+		PathRewrite: req.PathRewrite,
+		URL:         "https://" + hostname,
+	}
+
+	s.ports[id] = append(s.ports[id], port)
+	writeJSON(w, http.StatusOK, port)
+}
+
+func (s *Server) handleUnexposePort(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	portID := r.PathValue("portID")
+
+	i := slices.IndexFunc(s.ports[id], func(p *api.Port) bool { return p.ID == portID })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "port not found")
+		return
+	}
+
+	s.ports[id] = slices.Delete(s.ports[id], i, i+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleScanPorts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.plays[id]; !ok {
+		writeError(w, http.StatusNotFound, "play not found")
+		return
+	}
+
+	machine := r.URL.Query().Get("machine")
+
+	ports := []*api.ScannedPort{}
+	for _, p := range s.openPorts[id] {
+		if machine == "" || p.Machine == machine {
+			ports = append(ports, p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, ports)
+}
+
+func pointers[T any](values []T) []*T {
+	var ptrs []*T
+	for i := range values {
+		v := values[i]
+		ptrs = append(ptrs, &v)
+	}
+	return ptrs
+}
+
+func sortedValues[T any](m map[string]*T) []*T {
+	values := []*T{}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		values = append(values, m[k])
+	}
+	return values
+}
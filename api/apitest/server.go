@@ -0,0 +1,405 @@
+// Package apitest provides an in-memory fake of the iximiuz Labs API for
+// testing code built on top of api.Client without a real account.
+//
+// The fake keeps everything in memory: plays are created from registered
+// playgrounds and move through the same PlayState machine the real server
+// uses, port forwards, exposed ports and content files are stored per play or
+// per content item, and the play connection WebSocket streams status and task
+// updates as the state changes.
+package apitest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const (
+	// DefaultSessionID and DefaultAccessToken are the credentials the server
+	// accepts unless overridden via Options.
+	DefaultSessionID   = "apitest-session"
+	DefaultAccessToken = "apitest-token"
+
+	// DefaultUserID is the ID of the user the credentials belong to.
+	DefaultUserID = "apitest-user"
+)
+
+type Options struct {
+	SessionID   string
+	AccessToken string
+	UserID      string
+
+	// HoldCreated keeps newly created plays in the CREATED state until they
+	// are moved on explicitly with Server.Transition. By default, plays go
+	// straight to RUNNING, with all their machines ready and init tasks
+	// completed, which is what most tests want.
+	HoldCreated bool
+
+	// HoldInitTasks keeps init tasks in the CREATED state when a play starts
+	// running, leaving their progress to Server.SetTaskStatus.
+	HoldInitTasks bool
+}
+
+// Server is a running fake Labs API. It's safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	opts Options
+
+	mu sync.Mutex
+
+	nextID int
+
+	playgrounds map[string]*api.Playground
+	challenges  map[string]*api.Challenge
+	tutorials   map[string]*api.Tutorial
+
+	plays        map[string]*api.Play
+	portForwards map[string][]*api.PortForward
+	ports        map[string][]*api.Port
+	openPorts    map[string][]*api.ScannedPort
+
+	// Content files keyed by "<kind>/<name>" and then by file path.
+	files map[string]map[string][]byte
+
+	conns map[string]map[chan api.PlayConnMessage]struct{}
+
+	refuseConns bool
+
+	requests []string
+}
+
+// NewServer starts a fake Labs API server. Callers should Close it when done.
+func NewServer() *Server {
+	return NewServerWithOptions(Options{})
+}
+
+func NewServerWithOptions(opts Options) *Server {
+	if opts.SessionID == "" {
+		opts.SessionID = DefaultSessionID
+	}
+	if opts.AccessToken == "" {
+		opts.AccessToken = DefaultAccessToken
+	}
+	if opts.UserID == "" {
+		opts.UserID = DefaultUserID
+	}
+
+	s := &Server{
+		opts:         opts,
+		playgrounds:  make(map[string]*api.Playground),
+		challenges:   make(map[string]*api.Challenge),
+		tutorials:    make(map[string]*api.Tutorial),
+		plays:        make(map[string]*api.Play),
+		portForwards: make(map[string][]*api.PortForward),
+		ports:        make(map[string][]*api.Port),
+		openPorts:    make(map[string][]*api.ScannedPort),
+		files:        make(map[string]map[string][]byte),
+		conns:        make(map[string]map[chan api.PlayConnMessage]struct{}),
+	}
+
+	s.Server = httptest.NewServer(s.routes())
+
+	return s
+}
+
+// Client returns an api.Client pointed at the fake server and authenticated
+// with the credentials it accepts.
+func (s *Server) Client() *api.Client {
+	return api.NewClient(api.ClientOptions{
+		BaseURL:     s.URL,
+		APIBaseURL:  s.URL + "/api",
+		SessionID:   s.opts.SessionID,
+		AccessToken: s.opts.AccessToken,
+		UserAgent:   "labctl/apitest",
+	})
+}
+
+// Origin is the WebSocket origin to use with api.NewPlayConn.
+func (s *Server) Origin() string {
+	return s.URL
+}
+
+// Requests returns the "METHOD /path" lines of all requests served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// AddPlayground registers a playground that plays can be started from.
+func (s *Server) AddPlayground(pg api.Playground) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pg.PageURL == "" {
+		pg.PageURL = s.URL + "/playgrounds/" + pg.Name
+	}
+	pg.UserAccess = api.PlaygroundUserAccess{CanList: true, CanRead: true, CanStart: true}
+
+	s.playgrounds[pg.Name] = &pg
+}
+
+func (s *Server) AddChallenge(ch api.Challenge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ch.PageURL == "" {
+		ch.PageURL = s.URL + "/challenges/" + ch.Name
+	}
+
+	s.challenges[ch.Name] = &ch
+}
+
+func (s *Server) AddTutorial(tut api.Tutorial) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tut.PageURL == "" {
+		tut.PageURL = s.URL + "/tutorials/" + tut.Name
+	}
+
+	s.tutorials[tut.Name] = &tut
+}
+
+// SetOpenPorts sets what a port scan of the play's machine reports.
+func (s *Server) SetOpenPorts(playID, machine string, numbers ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ports []*api.ScannedPort
+	for _, p := range s.openPorts[playID] {
+		if p.Machine != machine {
+			ports = append(ports, p)
+		}
+	}
+	for _, n := range numbers {
+		ports = append(ports, &api.ScannedPort{Machine: machine, Number: n, Protocol: "tcp"})
+	}
+
+	s.openPorts[playID] = ports
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/auth/me", s.handleGetMe)
+	mux.HandleFunc("POST /api/sessions", s.handleCreateSession)
+	mux.HandleFunc("GET /api/sessions/{id}", s.handleGetSession)
+	mux.HandleFunc("DELETE /api/sessions/{id}", s.handleDeleteSession)
+
+	mux.HandleFunc("GET /api/playgrounds", s.handleListPlaygrounds)
+	mux.HandleFunc("GET /api/playgrounds/{name}", s.handleGetPlayground)
+	mux.HandleFunc("GET /api/challenges", s.handleListChallenges)
+	mux.HandleFunc("GET /api/challenges/{name}", s.handleGetChallenge)
+	mux.HandleFunc("GET /api/tutorials", s.handleListTutorials)
+	mux.HandleFunc("GET /api/tutorials/{name}", s.handleGetTutorial)
+
+	mux.HandleFunc("GET /api/plays", s.handleListPlays)
+	mux.HandleFunc("POST /api/plays", s.handleCreatePlay)
+	mux.HandleFunc("GET /api/plays/{id}", s.handleGetPlay)
+	mux.HandleFunc("DELETE /api/plays/{id}", s.handleDeletePlay)
+	mux.HandleFunc("POST /api/plays/{id}/actions", s.handlePlayAction)
+	mux.HandleFunc("GET /api/plays/{id}/tasks", s.handleGetPlayTasks)
+	mux.HandleFunc("POST /api/plays/{id}/conns", s.handleRequestPlayConn)
+	mux.HandleFunc("POST /api/plays/{id}/tunnels", s.handleStartTunnel)
+	mux.HandleFunc("GET /api/plays/{id}/port-forwards", s.handleListPortForwards)
+	mux.HandleFunc("POST /api/plays/{id}/port-forwards", s.handleAddPortForward)
+	mux.HandleFunc("DELETE /api/plays/{id}/port-forwards/{index}", s.handleRemovePortForward)
+	mux.HandleFunc("GET /api/plays/{id}/ports", s.handleListPorts)
+	mux.HandleFunc("POST /api/plays/{id}/ports", s.handleExposePort)
+	mux.HandleFunc("DELETE /api/plays/{id}/ports/{portID}", s.handleUnexposePort)
+	mux.HandleFunc("POST /api/plays/{id}/ports/scan", s.handleScanPorts)
+
+	mux.HandleFunc("GET /api/content/files", s.handleListContentFiles)
+	mux.HandleFunc("GET /api/content/v2/files", s.handleListContentFilesV2)
+	mux.HandleFunc("PUT /api/content/files", s.handleRequestContentUpload)
+	mux.HandleFunc("DELETE /api/content/files", s.handleDeleteContentFile)
+	mux.HandleFunc("PUT /api/content/markdown", s.handlePutContentMarkdown)
+
+	// Not under /api - these are the download, upload, WebSocket and tunnel
+	// login URLs the API hands out.
+	mux.HandleFunc("GET /content/files/{kinds}/{name}/{file...}", s.handleDownloadContentFile)
+	mux.HandleFunc("PUT /uploads/{kind}/{name}/{file...}", s.handleUploadContentFile)
+	mux.HandleFunc("GET /conns/{id}", s.handlePlayConn)
+	mux.HandleFunc("GET /tunnels/{id}/login", s.handleTunnelLogin)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/api/") && !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorized checks the Basic credentials api.Client sends. Session creation
+// is the one unauthenticated endpoint - it's how a login starts.
+func (s *Server) authorized(r *http.Request) bool {
+	if r.Method == http.MethodPost && r.URL.Path == "/api/sessions" {
+		return true
+	}
+
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.opts.SessionID+":"+s.opts.AccessToken))
+	return r.Header.Get("Authorization") == want
+}
+
+func (s *Server) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.Me{ID: s.opts.UserID})
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.Session{
+		ID:            s.opts.SessionID,
+		UserID:        s.opts.UserID,
+		Authenticated: true,
+		AccessToken:   s.opts.AccessToken,
+		AuthURL:       s.URL + "/auth/cli",
+	})
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") != s.opts.SessionID {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.Session{
+		ID:            s.opts.SessionID,
+		UserID:        s.opts.UserID,
+		Authenticated: true,
+	})
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListPlaygrounds(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSONWithETag(w, r, sortedValues(s.playgrounds))
+}
+
+func (s *Server) handleGetPlayground(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pg, ok := s.playgrounds[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "playground not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pg)
+}
+
+func (s *Server) handleListChallenges(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSONWithETag(w, r, sortedValues(s.challenges))
+}
+
+func (s *Server) handleGetChallenge(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch, ok := s.challenges[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "challenge not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ch)
+}
+
+func (s *Server) handleListTutorials(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSONWithETag(w, r, sortedValues(s.tutorials))
+}
+
+func (s *Server) handleGetTutorial(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tut, ok := s.tutorials[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tutorial not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tut)
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONWithETag answers conditional GETs the way the catalog endpoints of
+// the real API do: a strong ETag over the body and 304 on a match.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func readJSON(r *http.Request, into any) error {
+	if r.Body == nil {
+		return nil
+	}
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(into); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/content"
+)
+
+func newTestServer(t *testing.T, opts Options) *Server {
+	t.Helper()
+
+	s := NewServerWithOptions(opts)
+	t.Cleanup(s.Close)
+
+	s.AddPlayground(api.Playground{
+		Name: "docker",
+		Machines: []api.PlaygroundMachine{
+			{Name: "docker-01", Users: []api.MachineUser{{Name: "laborant", Default: true}}},
+		},
+		InitTasks: map[string]api.InitTask{
+			"init_docker": {Name: "init_docker", Machine: "docker-01", Init: true, Run: "dockerd"},
+		},
+	})
+
+	return s
+}
+
+func TestPlayLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, Options{})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+	assert.True(t, api.LooksLikePlayID(play.ID))
+	assert.True(t, play.StateIs(api.StateRunning))
+	assert.True(t, play.AllMachinesReady())
+	assert.True(t, play.IsInitialized())
+	assert.Positive(t, play.ExpiresIn)
+
+	_, err = client.SetPlayTitle(ctx, play.ID, "my-docker")
+	require.NoError(t, err)
+
+	play, err = client.StopPlay(ctx, play.ID)
+	require.NoError(t, err)
+	assert.True(t, play.StateIs(api.StateStopped))
+
+	recent, err := client.ListPlays(ctx, api.ListPlaysQueryParams{})
+	require.NoError(t, err)
+	assert.Empty(t, recent)
+
+	persistent, err := client.ListPlays(ctx, api.ListPlaysQueryParams{Persistent: true})
+	require.NoError(t, err)
+	require.Len(t, persistent, 1)
+	assert.Equal(t, "my-docker", persistent[0].Title)
+
+	play, err = client.RestartPlay(ctx, play.ID)
+	require.NoError(t, err)
+	assert.True(t, play.StateIs(api.StateRunning))
+
+	require.NoError(t, client.DestroyPlay(ctx, play.ID))
+
+	play, err = client.GetPlay(ctx, play.ID)
+	require.NoError(t, err)
+	assert.True(t, play.StateIs(api.StateDestroyed))
+
+	// A destroyed play can't be brought back.
+	_, err = client.RestartPlay(ctx, play.ID)
+	require.Error(t, err)
+}
+
+func TestUnknownPlayAndPlayground(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, Options{})
+	client := s.Client()
+
+	_, err := client.GetPlay(ctx, "ffffffffffffffffffffffff")
+	require.ErrorIs(t, err, api.ErrNotFound)
+
+	_, err = client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "nope"})
+	require.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestAuthenticationRequired(t *testing.T) {
+	s := newTestServer(t, Options{})
+
+	client := api.NewClient(api.ClientOptions{
+		BaseURL:     s.URL,
+		APIBaseURL:  s.URL + "/api",
+		SessionID:   "someone",
+		AccessToken: "else",
+	})
+
+	_, err := client.GetMe(context.Background())
+	require.ErrorIs(t, err, api.ErrAuthenticationRequired)
+
+	me, err := s.Client().GetMe(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUserID, me.ID)
+}
+
+func TestPortsAndPortForwards(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, Options{})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.AddPortForward(ctx, play.ID, api.PortForward{
+		Kind: "local", Machine: "docker-01", LocalPort: 8080, RemotePort: 80,
+	}))
+	pfs, err := client.ListPortForwards(ctx, play.ID)
+	require.NoError(t, err)
+	require.Len(t, pfs, 1)
+	assert.Equal(t, 80, pfs[0].RemotePort)
+
+	require.NoError(t, client.RemovePortForward(ctx, play.ID, 0))
+	pfs, err = client.ListPortForwards(ctx, play.ID)
+	require.NoError(t, err)
+	assert.Empty(t, pfs)
+
+	port, err := client.ExposePort(ctx, play.ID, api.ExposePortRequest{Machine: "docker-01", Number: 80})
+	require.NoError(t, err)
+	assert.Equal(t, api.AccessPrivate, port.AccessMode)
+
+	ports, err := client.ListPorts(ctx, play.ID)
+	require.NoError(t, err)
+	require.Len(t, ports, 1)
+
+	require.NoError(t, client.UnexposePort(ctx, play.ID, port.ID))
+	ports, err = client.ListPorts(ctx, play.ID)
+	require.NoError(t, err)
+	assert.Empty(t, ports)
+
+	s.SetOpenPorts(play.ID, "docker-01", 22, 80)
+	scanned, err := client.ScanPorts(ctx, play.ID, "docker-01")
+	require.NoError(t, err)
+	assert.Len(t, scanned, 2)
+}
+
+func TestStartTunnel(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, Options{HoldCreated: true})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+
+	// The client keeps retrying 503s, so only give it a moment to see one.
+	shortCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+
+	_, err = client.StartTunnel(shortCtx, play.ID, api.StartTunnelRequest{GenerateLoginURL: true})
+	require.Error(t, err)
+
+	require.NoError(t, s.Transition(play.ID, api.StateRunning))
+
+	resp, err := client.StartTunnel(ctx, play.ID, api.StartTunnelRequest{GenerateLoginURL: true})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.URL)
+	assert.NotEmpty(t, resp.LoginURL)
+}
+
+func TestPlayConnReceivesUpdates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := newTestServer(t, Options{HoldCreated: true, HoldInitTasks: true})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+	assert.True(t, play.StateIs(api.StateCreated))
+
+	conn := api.NewPlayConn(ctx, play, client, s.Origin())
+	require.NoError(t, conn.Start())
+	defer conn.Close()
+
+	go func() {
+		_ = s.Transition(play.ID, api.StateStarting, api.StateRunning)
+		_ = s.SetTaskStatus(play.ID, "init_docker", api.PlayTaskStatusCompleted)
+	}()
+
+	require.NoError(t, conn.WaitMachinesReady(5*time.Second, nil))
+	require.NoError(t, conn.WaitTasks(5*time.Second, true, nil))
+}
+
+func TestPlayConnReconnectsAndResyncs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := newTestServer(t, Options{HoldInitTasks: true})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+
+	conn := api.NewPlayConn(ctx, play, client, s.Origin())
+	require.NoError(t, conn.Start())
+	defer conn.Close()
+
+	// The task completes while the connection is down, so the update is never
+	// streamed - only the resync can deliver it.
+	s.RefuseConns(true)
+	s.DropConns(play.ID)
+
+	ev := <-conn.Events()
+	assert.Equal(t, api.PlayConnDisconnected, ev.Kind)
+	assert.Error(t, ev.Err)
+
+	require.NoError(t, s.SetTaskStatus(play.ID, "init_docker", api.PlayTaskStatusCompleted))
+	s.RefuseConns(false)
+
+	require.NoError(t, conn.WaitTasks(20*time.Second, true, nil))
+
+	ev = <-conn.Events()
+	assert.Equal(t, api.PlayConnReconnected, ev.Kind)
+	assert.GreaterOrEqual(t, ev.Attempts, 1)
+
+	ev = <-conn.Events()
+	assert.Equal(t, api.PlayConnResynced, ev.Kind)
+
+	// And the new connection streams live updates again.
+	go func() {
+		_ = s.Transition(play.ID, api.StateStopping, api.StateStopped)
+	}()
+	require.NoError(t, conn.WaitDone())
+}
+
+func TestPlayConnGivesUpOnGonePlay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s := newTestServer(t, Options{HoldInitTasks: true})
+	client := s.Client()
+
+	play, err := client.CreatePlay(ctx, api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+
+	conn := api.NewPlayConn(ctx, play, client, s.Origin())
+	require.NoError(t, conn.Start())
+	defer conn.Close()
+
+	require.NoError(t, client.DeletePlay(ctx, play.ID))
+	s.DropConns(play.ID)
+
+	var kinds []api.PlayConnEventKind
+	for ev := range conn.Events() {
+		kinds = append(kinds, ev.Kind)
+	}
+	assert.Equal(t, []api.PlayConnEventKind{api.PlayConnDisconnected, api.PlayConnReconnectFailed}, kinds)
+}
+
+func TestContentFiles(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, Options{})
+	client := s.Client()
+
+	src := filepath.Join(t.TempDir(), "index.md")
+	require.NoError(t, os.WriteFile(src, []byte("# Hello"), 0o644))
+
+	require.NoError(t, client.UploadContentFile(ctx, content.KindTutorial, "hello", "index.md", src))
+
+	data, ok := s.ContentFile(content.KindTutorial, "hello", "index.md")
+	require.True(t, ok)
+	assert.Equal(t, "# Hello", string(data))
+
+	files, err := client.ListContentFilesV2(ctx, content.KindTutorial, "hello")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "index.md", files[0].Path)
+	assert.Len(t, files[0].Digest, 32)
+
+	var buf bytes.Buffer
+	require.NoError(t, client.Download(ctx, "/content/files/tutorials/hello/index.md", nil, nil, &buf))
+	assert.Equal(t, "# Hello", buf.String())
+
+	require.NoError(t, client.DeleteContentFile(ctx, content.KindTutorial, "hello", "index.md"))
+	names, err := client.ListContentFiles(ctx, content.KindTutorial, "hello")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
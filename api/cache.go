@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// responseCache is an on-disk cache of GET responses revalidated with
+// ETag/Last-Modified. It's meant for the catalog listings, which are big,
+// rarely change, and are fetched on every shell tab completion.
+//
+// A cached entry is always revalidated - the cache saves the transfer (and the
+// server-side rendering) of an unchanged listing, not the round trip. The one
+// exception is being offline: then a stale entry beats no answer at all.
+type responseCache struct {
+	dir string
+}
+
+type cacheEntry struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	StoredAt     time.Time       `json:"storedAt"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// key scopes entries to the session - listings include the user's own
+// (private) content, so two accounts on one machine must not share them.
+func (rc *responseCache) key(sessionID, url string) string {
+	sum := sha256.Sum256([]byte(sessionID + "\n" + url))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (rc *responseCache) load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.Debug("Ignoring corrupted cache entry", "path", path, "error", err.Error())
+		return nil
+	}
+
+	return &entry
+}
+
+func (rc *responseCache) store(path string, entry *cacheEntry) error {
+	if err := os.MkdirAll(rc.dir, 0o700); err != nil {
+		return fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache entry: %w", err)
+	}
+
+	// Concurrent completions may write the same entry, hence the unique
+	// temp file - the last rename wins, and either version is fine.
+	file, err := os.CreateTemp(rc.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("unable to create cache entry: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to write cache entry: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to write cache entry: %w", err)
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// getCachedInto is GetInto backed by the response cache (when enabled).
+func (c *Client) getCachedInto(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	into any,
+) error {
+	if c.cache == nil {
+		return c.GetInto(ctx, path, query, nil, into)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.apiBaseURL+path, query, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	sessionID, _ := c.session()
+	key := c.cache.key(sessionID, req.URL.String())
+	entry := c.cache.load(key)
+
+	maxTries := uint(5)
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+
+		// With a fallback at hand, one attempt is enough - retrying an
+		// unreachable server only makes the tab completion hang.
+		maxTries = 1
+	}
+
+	resp, err := c.doRequestWithRetries(req, maxTries)
+	if err != nil {
+		var apiErr *APIError
+		switch {
+		case entry == nil:
+			return err
+
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotModified:
+			entry.StoredAt = time.Now()
+			if err := c.cache.store(key, entry); err != nil {
+				slog.Debug("Unable to refresh cache entry", "error", err.Error())
+			}
+
+		case errors.As(err, &apiErr) && !apiErr.Retryable():
+			// The server said "no" (e.g., the session is gone) - a cached
+			// answer would only mask that.
+			return err
+
+		default:
+			slog.Debug("Serving a stale cache entry",
+				"url", entry.URL, "storedAt", entry.StoredAt, "error", err.Error())
+		}
+
+		return json.Unmarshal(entry.Body, into)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(into); err != nil {
+		return err
+	}
+
+	if err := c.cache.store(key, &cacheEntry{
+		URL:          req.URL.String(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+		Body:         body,
+	}); err != nil {
+		// A broken cache must not break the command.
+		slog.Debug("Unable to store cache entry", "error", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingsAreCachedWithETags(t *testing.T) {
+	var requests, notModified atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name": "docker"}, {"name": "k3s"}]`))
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	newClient := func(sessionID string) *Client {
+		return NewClient(ClientOptions{
+			BaseURL:     srv.URL,
+			APIBaseURL:  srv.URL + "/api",
+			SessionID:   sessionID,
+			AccessToken: "token",
+			CacheDir:    cacheDir,
+		})
+	}
+
+	ctx := context.Background()
+	names := func(pgs []Playground) (names []string) {
+		for _, pg := range pgs {
+			names = append(names, pg.Name)
+		}
+		return names
+	}
+
+	// Cold cache - a full response.
+	pgs, err := newClient("s1").ListPlaygrounds(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker", "k3s"}, names(pgs))
+
+	// Warm cache - revalidated, the body comes from the disk.
+	pgs, err = newClient("s1").ListPlaygrounds(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker", "k3s"}, names(pgs))
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// Another session doesn't get to see the first one's entries.
+	_, err = newClient("s2").ListPlaygrounds(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// Server trouble - stale data it is, and without retries.
+	status.Store(http.StatusServiceUnavailable)
+	before := requests.Load()
+	pgs, err = newClient("s1").ListPlaygrounds(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker", "k3s"}, names(pgs))
+	assert.Equal(t, before+1, requests.Load())
+
+	// A definitive "no" is not masked by the cache.
+	status.Store(http.StatusUnauthorized)
+	_, err = newClient("s1").ListPlaygrounds(ctx, nil)
+	assert.ErrorIs(t, err, ErrAuthenticationRequired)
+
+	// Offline.
+	srv.Close()
+	pgs, err = newClient("s1").ListPlaygrounds(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker", "k3s"}, names(pgs))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestListingsWithoutCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientOptions{BaseURL: srv.URL, APIBaseURL: srv.URL + "/api"})
+	for range 2 {
+		_, err := client.ListTutorials(context.Background(), nil)
+		require.NoError(t, err)
+	}
+}
@@ -100,7 +100,7 @@ func (c *Client) ListChallenges(ctx context.Context, opts *ListChallengesOptions
 		query.Add("status", status)
 	}
 
-	return challenges, c.GetInto(ctx, "/challenges", query, nil, &challenges)
+	return challenges, c.getCachedInto(ctx, "/challenges", query, &challenges)
 }
 
 func (c *Client) ListAuthoredChallenges(ctx context.Context) ([]Challenge, error) {
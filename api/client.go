@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -11,11 +12,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
+	"github.com/gorilla/websocket"
 )
 
 var (
@@ -49,9 +51,26 @@ type Client struct {
 	sessionID   string
 	accessToken string
 
+	// credentials, if set, provides the session on the first request that
+	// needs it (see ClientOptions.Credentials).
+	credentials     func() (sessionID, accessToken string)
+	credentialsOnce sync.Once
+
 	userAgent string
 
 	httpClient *http.Client
+
+	// transport is nil when the Go defaults are used as is.
+	transport *http.Transport
+
+	// transportErr is a bad TransportOptions setting. NewClient can't fail,
+	// so the error surfaces on the first request instead.
+	transportErr error
+
+	// cache is nil when response caching is disabled.
+	cache *responseCache
+
+	tracer Tracer
 }
 
 type ClientOptions struct {
@@ -60,24 +79,94 @@ type ClientOptions struct {
 	SessionID   string
 	AccessToken string
 	UserAgent   string
+
+	// Credentials, when set, takes the place of SessionID and AccessToken
+	// and is called only once the client makes its first request - e.g.,
+	// to not unlock a credentials store for the commands that never do.
+	Credentials func() (sessionID, accessToken string)
+
+	Transport TransportOptions
+
+	// CacheDir enables the on-disk cache of catalog listings (playgrounds,
+	// challenges, tutorials). Empty means no caching.
+	CacheDir string
+
+	// Tracer, when set, observes every HTTP request and WebSocket handshake
+	// the client makes.
+	Tracer Tracer
+}
+
+// Tracer observes the client's traffic, e.g., to record it for a bug report.
+type Tracer interface {
+	// WrapTransport returns a RoundTripper that records the exchanges going
+	// through next.
+	WrapTransport(next http.RoundTripper) http.RoundTripper
+
+	// TraceWebSocket records a WebSocket handshake. The response is nil if
+	// the handshake didn't get that far.
+	TraceWebSocket(url string, header http.Header, resp *http.Response, err error, started time.Time)
 }
 
 func NewClient(opts ClientOptions) *Client {
+	var (
+		transport    *http.Transport
+		transportErr error
+	)
+	if !opts.Transport.IsZero() {
+		transport, transportErr = NewTransport(opts.Transport)
+		if transportErr != nil {
+			transportErr = fmt.Errorf("invalid transport settings: %w", transportErr)
+		}
+	}
+
+	var roundTripper http.RoundTripper = http.DefaultTransport
+	if transport != nil {
+		roundTripper = transport
+	}
+
+	httpClient := http.DefaultClient
+	if opts.Tracer != nil {
+		httpClient = &http.Client{Transport: opts.Tracer.WrapTransport(roundTripper)}
+	} else if transport != nil {
+		httpClient = &http.Client{Transport: transport}
+	}
+
+	var cache *responseCache
+	if opts.CacheDir != "" {
+		cache = &responseCache{dir: opts.CacheDir}
+	}
+
 	return &Client{
-		baseURL:     opts.BaseURL,
-		apiBaseURL:  opts.APIBaseURL,
-		sessionID:   opts.SessionID,
-		accessToken: opts.AccessToken,
-		userAgent:   opts.UserAgent,
-		httpClient:  http.DefaultClient,
+		baseURL:      opts.BaseURL,
+		apiBaseURL:   opts.APIBaseURL,
+		sessionID:    opts.SessionID,
+		accessToken:  opts.AccessToken,
+		credentials:  opts.Credentials,
+		userAgent:    opts.UserAgent,
+		httpClient:   httpClient,
+		transport:    transport,
+		transportErr: transportErr,
+		cache:        cache,
+		tracer:       opts.Tracer,
 	}
 }
 
 func (c *Client) SetCredentials(sessionID, accessToken string) {
+	c.credentialsOnce.Do(func() {})
 	c.sessionID = sessionID
 	c.accessToken = accessToken
 }
 
+// session returns the credentials the requests are made with.
+func (c *Client) session() (sessionID, accessToken string) {
+	c.credentialsOnce.Do(func() {
+		if c.credentials != nil {
+			c.sessionID, c.accessToken = c.credentials()
+		}
+	})
+	return c.sessionID, c.accessToken
+}
+
 func (c *Client) Get(
 	ctx context.Context,
 	path string,
@@ -340,6 +429,10 @@ func (c *Client) newRequest(
 	headers http.Header,
 	body io.Reader,
 ) (*http.Request, error) {
+	if c.transportErr != nil {
+		return nil, c.transportErr
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
@@ -353,8 +446,8 @@ func (c *Client) newRequest(
 	req.Header.Set("User-Agent", c.userAgent)
 
 	if strings.HasPrefix(url, c.baseURL) || strings.HasPrefix(url, c.apiBaseURL) {
-		if c.sessionID != "" && c.accessToken != "" {
-			req.Header.Set("Authorization", "Basic "+base64Encode(c.sessionID+":"+c.accessToken))
+		if sessionID, accessToken := c.session(); sessionID != "" && accessToken != "" {
+			req.Header.Set("Authorization", "Basic "+base64Encode(sessionID+":"+accessToken))
 		}
 	}
 
@@ -366,9 +459,20 @@ func (c *Client) newRequest(
 }
 
 func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	return c.doRequestWithRetries(req, 5)
+}
+
+func (c *Client) doRequestWithRetries(req *http.Request, maxTries uint) (*http.Response, error) {
 	operation := func() (*http.Response, error) {
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			// An untrusted certificate won't become trusted on a retry - it's
+			// a missing CA bundle or an intercepting proxy.
+			var certErr *tls.CertificateVerificationError
+			if errors.As(err, &certErr) {
+				return nil, backoff.Permanent(err)
+			}
+
 			return nil, err
 		}
 
@@ -376,49 +480,54 @@ func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
 			body, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
 
-			switch resp.StatusCode {
-			case http.StatusUnauthorized:
-				return nil, backoff.Permanent(ErrAuthenticationRequired)
-
-			case http.StatusNotFound:
-				return nil, backoff.Permanent(ErrNotFound)
-
-			case http.StatusGatewayTimeout:
-				return nil, ErrGatewayTimeout
-
-			case http.StatusServiceUnavailable:
+			apiErr := newAPIError(req, resp, body)
+			if apiErr.Retryable() {
 				// "Not yet" rather than "no" - the server says so explicitly
 				// (e.g. a machine that's still booting), so it's retryable.
-				return nil, ErrServiceUnavailable
-
-			case http.StatusTooManyRequests:
-				if reset, e := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Reset"), 10, 0); e == nil && reset > 0 {
-					return nil, backoff.RetryAfter(retryAfterSeconds(reset))
-				}
-
-				return nil, ErrRateLimitExceeded
+				return nil, apiErr
 			}
 
-			return nil, backoff.Permanent(fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body))
+			return nil, backoff.Permanent(apiErr)
 		}
 
 		return resp, nil
 	}
 
-	maxRetries := uint(5)
 	if req.Body != nil && req.GetBody == nil { // Body cannot be reset, so we can only read it once
-		maxRetries = 1
+		maxTries = 1
 	}
 
 	return backoff.Retry(
 		req.Context(),
 		operation,
-		backoff.WithMaxTries(maxRetries),
+		backoff.WithMaxTries(maxTries),
 		backoff.WithMaxElapsedTime(10*time.Second),
 		backoff.WithBackOff(backoff.NewExponentialBackOff()),
 	)
 }
 
+// dialWebSocket is the single place the client opens WebSockets through, so
+// that the handshakes show up in traces next to the REST calls.
+func (c *Client) dialWebSocket(
+	ctx context.Context,
+	dialer *websocket.Dialer,
+	url string,
+	header http.Header,
+) (*websocket.Conn, error) {
+	if c.transportErr != nil {
+		return nil, c.transportErr
+	}
+
+	started := time.Now()
+
+	conn, resp, err := c.WebSocketDialer(dialer).DialContext(ctx, url, header)
+	if c.tracer != nil {
+		c.tracer.TraceWebSocket(url, header, resp, err, started)
+	}
+
+	return conn, err
+}
+
 // maxRetryAfterSeconds bounds the wait derived from an X-Ratelimit-Reset
 // header. Without a bound, a bogus or far-future reset value overflows
 // time.Duration inside backoff.RetryAfter (which computes seconds*time.Second)
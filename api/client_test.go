@@ -1,6 +1,9 @@
 package api
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -35,3 +38,49 @@ func TestRetryAfterSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestLazyCredentials(t *testing.T) {
+	var calls int
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientOptions{
+		BaseURL:    srv.URL,
+		APIBaseURL: srv.URL,
+		Credentials: func() (string, string) {
+			calls++
+			return "session", "token"
+		},
+	})
+	if calls != 0 {
+		t.Fatalf("credentials resolved before the first request")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := client.GetInto(context.Background(), "/me", nil, nil, &map[string]any{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("credentials resolved %d times, want 1", calls)
+	}
+	for _, a := range auth {
+		if a != "Basic "+base64Encode("session:token") {
+			t.Fatalf("unexpected Authorization header %q", a)
+		}
+	}
+
+	// Explicitly set credentials win.
+	client = NewClient(ClientOptions{BaseURL: srv.URL, APIBaseURL: srv.URL, Credentials: func() (string, string) {
+		calls++
+		return "session", "token"
+	}})
+	client.SetCredentials("", "")
+	if sessionID, _ := client.session(); sessionID != "" || calls != 1 {
+		t.Fatalf("SetCredentials didn't take precedence")
+	}
+}
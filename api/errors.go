@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+// requestIDHeaders are the response headers that may carry an ID the server
+// side can look the request up by, in order of preference.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"Fly-Request-Id",
+	"Cf-Ray",
+}
+
+// maxErrorMessageLen bounds how much of a non-JSON error body ends up in the
+// error message - an HTML error page from a proxy is of no use in full.
+const maxErrorMessageLen = 512
+
+// APIError is returned for any non-2xx answer from the server. It satisfies
+// errors.Is for the package's sentinel errors (ErrNotFound,
+// ErrAuthenticationRequired, etc.), so existing checks keep working, while
+// callers that need the details can get them with errors.As.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+
+	// RequestID is the server-side ID of the request, if the response had one.
+	// It's what to quote in a bug report.
+	RequestID string
+
+	// RetryAfter is how long the server asked to wait before trying again
+	// (Retry-After or X-Ratelimit-Reset). Zero means the server didn't say.
+	RetryAfter time.Duration
+
+	// Message is the server's explanation - the "message" (or "error") field
+	// of a JSON error body, or the body itself if it isn't JSON.
+	Message string
+
+	// Code is the machine-readable error code from a JSON error body, if any.
+	Code string
+
+	// Body is the decoded JSON error body, or nil if it wasn't a JSON object.
+	Body map[string]any
+}
+
+var _ error = (*APIError)(nil)
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RetryAfter: parseRetryAfter(resp.Header),
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(body, &decoded); err == nil && decoded != nil {
+		e.Body = decoded
+		e.Message = firstString(decoded, "message", "error", "statusMessage")
+		e.Code = firstString(decoded, "code")
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+		if len(e.Message) > maxErrorMessageLen {
+			e.Message = e.Message[:maxErrorMessageLen] + "..."
+		}
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		if s := e.sentinel(); s != nil {
+			msg = s.Error()
+		} else {
+			msg = strings.ToLower(http.StatusText(e.StatusCode))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s %s: status %d", msg, e.Method, e.Path, e.StatusCode)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", request ID %s", e.RequestID)
+	}
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// Is makes errors.Is(err, ErrNotFound) and friends work for API errors with
+// the matching status code.
+func (e *APIError) Is(target error) bool {
+	s := e.sentinel()
+	return s != nil && s == target
+}
+
+// Unwrap exposes the server's retry hint as a *backoff.RetryAfterError, so
+// backoff.Retry waits exactly as long as the server asked.
+func (e *APIError) Unwrap() error {
+	if e.RetryAfter > 0 {
+		return &backoff.RetryAfterError{Duration: e.RetryAfter}
+	}
+	return nil
+}
+
+// Retryable reports whether the server said "not yet" rather than "no": the
+// service is unavailable for now, the hop in front of it timed out, or the
+// client is being rate limited. Anything else is the server's final answer.
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
+
+func (e *APIError) sentinel() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrAuthenticationRequired
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusGatewayTimeout:
+		return ErrGatewayTimeout
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	case http.StatusTooManyRequests:
+		return ErrRateLimitExceeded
+	}
+	return nil
+}
+
+// parseRetryAfter reads the server's retry hint from the standard Retry-After
+// header (seconds or an HTTP date) or, failing that, from X-Ratelimit-Reset.
+func parseRetryAfter(h http.Header) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs > 0 {
+			return time.Duration(min(secs, maxRetryAfterSeconds)) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return time.Duration(retryAfterSeconds(at.Unix())) * time.Second
+		}
+	}
+
+	if reset, err := strconv.ParseInt(h.Get("X-Ratelimit-Reset"), 10, 0); err == nil && reset > 0 {
+		return time.Duration(retryAfterSeconds(reset)) * time.Second
+	}
+
+	return 0
+}
+
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
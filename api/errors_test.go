@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorFromResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "play not found", "code": "PLAY_NOT_FOUND"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientOptions{BaseURL: srv.URL, APIBaseURL: srv.URL + "/api"})
+
+	_, err := client.GetPlay(context.Background(), "0123456789abcdef01234567")
+	require.Error(t, err)
+
+	// The sentinel keeps working...
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrAuthenticationRequired)
+
+	// ...and the details are there for those who need them.
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, "/api/plays/0123456789abcdef01234567", apiErr.Path)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.Equal(t, "play not found", apiErr.Message)
+	assert.Equal(t, "PLAY_NOT_FOUND", apiErr.Code)
+	assert.False(t, apiErr.Retryable())
+
+	assert.Equal(t, "play not found (GET /api/plays/0123456789abcdef01234567: status 404, request ID req-123)", err.Error())
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	cases := []struct {
+		status    int
+		sentinel  error
+		retryable bool
+	}{
+		{http.StatusUnauthorized, ErrAuthenticationRequired, false},
+		{http.StatusNotFound, ErrNotFound, false},
+		{http.StatusGatewayTimeout, ErrGatewayTimeout, true},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable, true},
+		{http.StatusTooManyRequests, ErrRateLimitExceeded, true},
+		{http.StatusBadRequest, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			err := &APIError{StatusCode: tc.status, Method: "GET", Path: "/api/x"}
+
+			if tc.sentinel != nil {
+				assert.ErrorIs(t, err, tc.sentinel)
+				assert.Contains(t, err.Error(), tc.sentinel.Error())
+			}
+			for _, other := range []error{ErrAuthenticationRequired, ErrNotFound, ErrGatewayTimeout, ErrServiceUnavailable, ErrRateLimitExceeded} {
+				if other != tc.sentinel {
+					assert.NotErrorIs(t, err, other)
+				}
+			}
+			assert.Equal(t, tc.retryable, err.Retryable())
+		})
+	}
+}
+
+func TestAPIErrorNonJSONBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/plays", nil)
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}
+
+	err := newAPIError(req, resp, []byte("  <html>"+strings.Repeat("x", 1000)+"</html>\n"))
+	assert.Nil(t, err.Body)
+	assert.True(t, strings.HasPrefix(err.Message, "<html>"))
+	assert.LessOrEqual(t, len(err.Message), maxErrorMessageLen+3)
+
+	err = newAPIError(req, resp, nil)
+	assert.Equal(t, "bad gateway (POST /api/plays: status 502)", err.Error())
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/plays", nil)
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	err := newAPIError(req, resp, nil)
+	assert.Equal(t, 7*time.Second, err.RetryAfter)
+
+	// backoff.Retry must see the hint to honor it.
+	var retryAfter *backoff.RetryAfterError
+	require.True(t, errors.As(err, &retryAfter))
+	assert.Equal(t, 7*time.Second, retryAfter.Duration)
+
+	resp.Header = http.Header{}
+	resp.Header.Set("X-Ratelimit-Reset", "30")
+	assert.Equal(t, 30*time.Second, newAPIError(req, resp, nil).RetryAfter)
+
+	resp.Header = http.Header{}
+	err = newAPIError(req, resp, nil)
+	assert.Zero(t, err.RetryAfter)
+	assert.False(t, errors.As(err, &retryAfter))
+}
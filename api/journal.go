@@ -59,7 +59,7 @@ func (c *Client) StreamPlayJournal(
 	stdout io.Writer,
 	stderr io.Writer,
 ) error {
-	conn, _, err := journalDialer.DialContext(ctx, streamURL, http.Header{
+	conn, err := c.dialWebSocket(ctx, journalDialer, streamURL, http.Header{
 		"Origin": {origin},
 	})
 	if err != nil {
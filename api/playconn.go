@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/cenkalti/backoff/v5"
 	"github.com/gorilla/websocket"
 )
 
@@ -24,6 +25,10 @@ type PlayConnMessage struct {
 	Machine string      `json:"machine,omitempty"`
 	Task    PlayTask    `json:"task,omitempty"`
 	Status  *PlayStatus `json:"status,omitempty"`
+
+	// Resync marks the messages made up from a fresh copy of the play after
+	// a reconnect (as opposed to the ones the server streamed).
+	Resync bool `json:"-"`
 }
 
 type PlayConn struct {
@@ -35,14 +40,47 @@ type PlayConn struct {
 	client *Client
 	origin string
 
+	mu   sync.Mutex
 	conn *websocket.Conn
 
-	msgCh chan PlayConnMessage
-	errCh chan error
+	msgCh   chan PlayConnMessage
+	errCh   chan error
+	eventCh chan PlayConnEvent
 
 	closeOnce sync.Once
 }
 
+type PlayConnEventKind string
+
+const (
+	// PlayConnDisconnected means the connection dropped and a reconnect is
+	// about to be attempted. Err says why it dropped.
+	PlayConnDisconnected PlayConnEventKind = "disconnected"
+
+	// PlayConnReconnected means the connection is back. The resync messages
+	// (if any) follow.
+	PlayConnReconnected PlayConnEventKind = "reconnected"
+
+	// PlayConnResynced means the play snapshot has been brought up to date
+	// with the server after a reconnect - all the Resync messages are out.
+	PlayConnResynced PlayConnEventKind = "resynced"
+
+	// PlayConnReconnectFailed means the connection is gone for good. The
+	// same error is also reported to the waiters.
+	PlayConnReconnectFailed PlayConnEventKind = "reconnect_failed"
+)
+
+// PlayConnEvent reports a change in the connection itself, as opposed to the
+// play (which is what PlayConnMessage is about).
+type PlayConnEvent struct {
+	Kind PlayConnEventKind
+
+	// Attempts is the number of dials the reconnect took.
+	Attempts int
+
+	Err error
+}
+
 func NewPlayConn(
 	ctx context.Context,
 	play *Play,
@@ -52,11 +90,12 @@ func NewPlayConn(
 	ctx, cancel := context.WithCancel(ctx)
 
 	return &PlayConn{
-		ctx:    ctx,
-		cancel: cancel,
-		play:   play,
-		client: client,
-		origin: origin,
+		ctx:     ctx,
+		cancel:  cancel,
+		play:    play,
+		client:  client,
+		origin:  origin,
+		eventCh: make(chan PlayConnEvent, 16),
 	}
 }
 
@@ -67,6 +106,11 @@ var (
 		// Smaller timeout than in the default dialer, but we'll do more attempts.
 		HandshakeTimeout: 30 * time.Second,
 	}
+
+	// playConnReconnectTimeout bounds how long a dropped connection is being
+	// re-established before the waiters get an error. It's generous because
+	// the play itself is most likely fine - it's the user's Wi-Fi that isn't.
+	playConnReconnectTimeout = 5 * time.Minute
 )
 
 func (pc *PlayConn) Start() error {
@@ -78,13 +122,10 @@ func (pc *PlayConn) Start() error {
 	// Retry connection with exponential backoff
 	var conn *websocket.Conn
 	maxRetries := 10
-	baseDelay := 500 * time.Millisecond
-	maxDelay := 5 * time.Second
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff: 500ms, 1s, 2s, 4s, 5s, 5s, 5s, 5s, 5s, 5s
-			delay := max(maxDelay, baseDelay*time.Duration(1<<uint(attempt-1)))
+			delay := dialRetryDelay(attempt)
 			slog.Debug("Retrying WebSocket connection", "attempt", attempt+1, "delay", delay)
 
 			select {
@@ -94,7 +135,7 @@ func (pc *PlayConn) Start() error {
 			}
 		}
 
-		conn, _, err = playConnDialer.DialContext(pc.ctx, hconn.URL, http.Header{
+		conn, err = pc.client.dialWebSocket(pc.ctx, playConnDialer, hconn.URL, http.Header{
 			"Origin": {pc.origin},
 		})
 		if err == nil {
@@ -114,59 +155,212 @@ func (pc *PlayConn) Start() error {
 		}
 	}
 
-	pc.conn = conn
+	if !pc.setConn(conn) {
+		return fmt.Errorf("play connection closed while connecting: %w", pc.ctx.Err())
+	}
 
 	pc.msgCh = make(chan PlayConnMessage, 1024)
 	pc.errCh = make(chan error, 1)
 
-	go func() {
-		// The reader is the sole sender on msgCh/errCh, so it owns closing them -
-		// doing it here (and nowhere else) makes the close race-free even when
-		// Close() is called concurrently from another goroutine. cancel() wakes
-		// any waiters that were blocked on the channels.
-		defer func() {
-			pc.cancel()
-			close(pc.msgCh)
-			close(pc.errCh)
-		}()
-
-		sendErr := func(err error) {
-			select {
-			case pc.errCh <- err:
-			case <-pc.ctx.Done():
-			}
+	go pc.read()
+
+	return nil
+}
+
+// Events returns the connection's disconnect/reconnect notifications. Events
+// are dropped if nobody reads them. The channel is closed when the connection
+// is done for good (after a successful Start).
+func (pc *PlayConn) Events() <-chan PlayConnEvent {
+	return pc.eventCh
+}
+
+func (pc *PlayConn) read() {
+	// The reader is the sole sender on msgCh/errCh, so it owns closing them -
+	// doing it here (and nowhere else) makes the close race-free even when
+	// Close() is called concurrently from another goroutine. cancel() wakes
+	// any waiters that were blocked on the channels.
+	defer func() {
+		pc.cancel()
+		close(pc.msgCh)
+		close(pc.errCh)
+		close(pc.eventCh)
+	}()
+
+	sendErr := func(err error) {
+		select {
+		case pc.errCh <- err:
+		case <-pc.ctx.Done():
 		}
+	}
+
+	send := func(msg PlayConnMessage) bool {
+		select {
+		case pc.msgCh <- msg:
+			return true
+		case <-pc.ctx.Done():
+			return false
+		}
+	}
+
+	for pc.ctx.Err() == nil {
+		_, message, err := pc.currentConn().ReadMessage()
+		if err != nil {
+			if pc.ctx.Err() != nil {
+				return // closed by us
+			}
+			if err == io.EOF || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return // terminal error
+			}
+
+			// A failed read leaves a gorilla connection broken for good, so
+			// whatever the reason, the only way forward is a new connection.
+			slog.Warn("Play connection lost, reconnecting...", "error", err.Error())
+			pc.emit(PlayConnEvent{Kind: PlayConnDisconnected, Err: err})
 
-		for pc.ctx.Err() == nil {
-			_, message, err := pc.conn.ReadMessage()
+			attempts, err := pc.reconnect()
 			if err != nil {
-				if err == io.EOF || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
-					return // terminal error
-				}
-				if websocket.IsUnexpectedCloseError(err) {
+				if pc.ctx.Err() == nil {
+					pc.emit(PlayConnEvent{Kind: PlayConnReconnectFailed, Attempts: attempts, Err: err})
 					sendErr(fmt.Errorf("play connection WebSocket closed unexpectedly: %w", err))
-					return // terminal error
 				}
-
-				sendErr(fmt.Errorf("error reading play connection message: %w", err))
-				continue // non-terminal error
+				return // terminal error
 			}
 
-			var msg PlayConnMessage
-			if err := json.Unmarshal(message, &msg); err != nil {
-				sendErr(fmt.Errorf("error decoding play connection message: %w", err))
-				continue // non-terminal error
+			slog.Info("Play connection re-established", "attempts", attempts)
+			pc.emit(PlayConnEvent{Kind: PlayConnReconnected, Attempts: attempts})
+
+			// Whatever happened while the connection was down is only visible
+			// in the play itself, so the snapshot is brought up to date via the
+			// same path live updates take - the waiters own it.
+			msgs, err := pc.resync()
+			if err != nil {
+				slog.Warn("Couldn't resync the playground state after reconnecting", "error", err.Error())
+				continue
+			}
+			for _, msg := range msgs {
+				if !send(msg) {
+					return
+				}
 			}
 
-			select {
-			case pc.msgCh <- msg:
-			case <-pc.ctx.Done():
-				return
+			pc.emit(PlayConnEvent{Kind: PlayConnResynced})
+			continue
+		}
+
+		var msg PlayConnMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			sendErr(fmt.Errorf("error decoding play connection message: %w", err))
+			continue // non-terminal error
+		}
+
+		if !send(msg) {
+			return
+		}
+	}
+}
+
+// dialRetryDelay is the exponential backoff of the initial dial attempts:
+// 500ms, 1s, 2s, 4s, 5s, 5s, 5s, 5s, 5s (capped at 5s).
+func dialRetryDelay(attempt int) time.Duration {
+	const (
+		baseDelay = 500 * time.Millisecond
+		maxDelay  = 5 * time.Second
+	)
+	return min(maxDelay, baseDelay*time.Duration(1<<uint(attempt-1)))
+}
+
+// reconnect re-requests a connection handle (the old one may have expired
+// together with the connection) and dials it, with backoff, until it works,
+// the server says the play is gone, or playConnReconnectTimeout runs out.
+func (pc *PlayConn) reconnect() (int, error) {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 500 * time.Millisecond
+	b.MaxInterval = 10 * time.Second
+
+	attempts := 0
+	_, err := backoff.Retry(pc.ctx, func() (struct{}, error) {
+		attempts++
+
+		hconn, err := pc.client.RequestPlayConn(pc.ctx, pc.play.ID)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && !apiErr.Retryable() {
+				return struct{}{}, backoff.Permanent(err)
 			}
+			return struct{}{}, err
 		}
-	}()
 
-	return nil
+		conn, err := pc.client.dialWebSocket(pc.ctx, playConnDialer, hconn.URL, http.Header{
+			"Origin": {pc.origin},
+		})
+		if err != nil {
+			slog.Debug("Play connection reconnect attempt failed", "attempt", attempts, "error", err)
+			return struct{}{}, err
+		}
+
+		if !pc.setConn(conn) {
+			return struct{}{}, backoff.Permanent(pc.ctx.Err())
+		}
+		return struct{}{}, nil
+	},
+		backoff.WithBackOff(b),
+		backoff.WithMaxElapsedTime(playConnReconnectTimeout),
+	)
+
+	return attempts, err
+}
+
+// resync turns a fresh copy of the play into messages covering everything
+// the local snapshot tracks. A failed fetch isn't fatal - live updates will
+// catch up eventually.
+func (pc *PlayConn) resync() ([]PlayConnMessage, error) {
+	play, err := pc.client.GetPlay(pc.ctx, pc.play.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []PlayConnMessage
+	if play.Status != nil {
+		msgs = append(msgs, PlayConnMessage{Kind: "status", Status: play.Status, Resync: true})
+	}
+	for _, task := range play.Tasks {
+		msgs = append(msgs, PlayConnMessage{Kind: "task", Task: task, Resync: true})
+	}
+
+	return msgs, nil
+}
+
+func (pc *PlayConn) emit(ev PlayConnEvent) {
+	select {
+	case pc.eventCh <- ev:
+	default:
+	}
+}
+
+// setConn swaps in a new connection, closing the previous one. It refuses
+// (and closes conn) if the PlayConn has been closed in the meantime.
+func (pc *PlayConn) setConn(conn *websocket.Conn) bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.ctx.Err() != nil {
+		conn.Close()
+		return false
+	}
+
+	if pc.conn != nil {
+		pc.conn.Close()
+	}
+	pc.conn = conn
+
+	return true
+}
+
+func (pc *PlayConn) currentConn() *websocket.Conn {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	return pc.conn
 }
 
 // Close stops the play connection. It cancels the connection context and closes
@@ -175,8 +369,13 @@ func (pc *PlayConn) Start() error {
 // reader (it never closes the channels itself).
 func (pc *PlayConn) Close() {
 	pc.closeOnce.Do(func() {
+		pc.mu.Lock()
+		defer pc.mu.Unlock()
+
 		pc.cancel()
-		pc.conn.Close()
+		if pc.conn != nil {
+			pc.conn.Close()
+		}
 	})
 }
 
@@ -355,6 +554,37 @@ func (pc *PlayConn) WaitMachinesReady(timeout time.Duration, s *spinner.Spinner)
 	return ctx.Err()
 }
 
+// Watch calls fn with every message received over the play connection (after
+// folding it into the play snapshot) until the connection ends or fn returns an
+// error, which Watch then returns.
+func (pc *PlayConn) Watch(fn func(PlayConnMessage) error) error {
+	for pc.ctx.Err() == nil {
+		select {
+		case <-pc.ctx.Done():
+			return pc.ctx.Err()
+
+		case err, ok := <-pc.errCh:
+			if !ok {
+				return pc.ctx.Err()
+			}
+
+			slog.Warn("Play connection error", "error", err.Error())
+
+		case msg, ok := <-pc.msgCh:
+			if !ok {
+				return pc.ctx.Err()
+			}
+
+			pc.applyMessage(msg)
+			if err := fn(msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return pc.ctx.Err()
+}
+
 func (pc *PlayConn) WaitDone() error {
 	for pc.ctx.Err() == nil {
 		select {
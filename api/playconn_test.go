@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialRetryDelay(t *testing.T) {
+	var delays []time.Duration
+	for attempt := 1; attempt < 10; attempt++ {
+		delays = append(delays, dialRetryDelay(attempt))
+	}
+
+	assert.Equal(t, []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	}, delays)
+}
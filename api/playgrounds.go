@@ -58,7 +58,7 @@ func (c *Client) ListPlaygrounds(ctx context.Context, opts *ListPlaygroundsOptio
 		q.Add("filter", opts.Filter)
 	}
 
-	return plays, c.GetInto(ctx, "/playgrounds", q, nil, &plays)
+	return plays, c.getCachedInto(ctx, "/playgrounds", q, &plays)
 }
 
 type PlaygroundNetwork struct {
@@ -110,11 +110,12 @@ type MachineResources struct {
 }
 
 type MachineStartupFile struct {
-	Path    string `json:"path"`
-	Content string `json:"content"`
-	Mode    string `json:"mode,omitempty"`
-	Owner   string `json:"owner,omitempty"`
-	Append  bool   `json:"append,omitempty"`
+	Path        string `json:"path"`
+	Content     string `yaml:"content,omitempty" json:"content"`
+	ContentFrom string `yaml:"contentFrom,omitempty" json:"-"`
+	Mode        string `json:"mode,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Append      bool   `json:"append,omitempty"`
 }
 
 type MachineBackend string
@@ -234,7 +235,8 @@ type InitTask struct {
 	User           string          `yaml:"user" json:"user"`
 	TimeoutSeconds int             `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty"`
 	Needs          []string        `yaml:"needs,omitempty" json:"needs,omitempty"`
-	Run            string          `yaml:"run" json:"run"`
+	Run            string          `yaml:"run,omitempty" json:"run"`
+	RunFrom        string          `yaml:"runFrom,omitempty" json:"-"`
 	Status         int             `yaml:"status,omitempty" json:"status,omitempty"`
 	Conditions     []InitCondition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
 }
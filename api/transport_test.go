@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportCAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "play-1"}`))
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0o600))
+
+	// Untrusted by default - and that's not worth retrying.
+	started := time.Now()
+	client := NewClient(ClientOptions{BaseURL: srv.URL, APIBaseURL: srv.URL + "/api"})
+	_, err := client.GetPlay(context.Background(), "play-1")
+	var certErr *tls.CertificateVerificationError
+	require.ErrorAs(t, err, &certErr)
+	assert.Less(t, time.Since(started), time.Second)
+
+	client = NewClient(ClientOptions{
+		BaseURL:    srv.URL,
+		APIBaseURL: srv.URL + "/api",
+		Transport:  TransportOptions{CAFile: caFile},
+	})
+	play, err := client.GetPlay(context.Background(), "play-1")
+	require.NoError(t, err)
+	assert.Equal(t, "play-1", play.ID)
+
+	dialer := client.WebSocketDialer(nil)
+	require.NotNil(t, dialer.TLSClientConfig)
+	assert.Same(t, client.transport.TLSClientConfig, dialer.TLSClientConfig)
+}
+
+func TestTransportProxyAuth(t *testing.T) {
+	var proxyAuth, proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		proxiedURL = r.URL.String()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "play-1"}`))
+	}))
+	defer proxy.Close()
+
+	client := NewClient(ClientOptions{
+		BaseURL:    "http://labs.example.com",
+		APIBaseURL: "http://labs.example.com/api",
+		Transport: TransportOptions{
+			ProxyURL:    "http://alice:s3cret@" + proxy.Listener.Addr().String(),
+			DialTimeout: 5 * time.Second,
+		},
+	})
+
+	_, err := client.GetPlay(context.Background(), "play-1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://labs.example.com/api/plays/play-1", proxiedURL)
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("alice:s3cret")), proxyAuth)
+
+	proxyURL, err := client.WebSocketDialer(nil).Proxy(httptest.NewRequest(http.MethodGet, "https://labs.example.com/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, proxy.Listener.Addr().String(), proxyURL.Host)
+}
+
+func TestTransportInvalidSettings(t *testing.T) {
+	client := NewClient(ClientOptions{
+		BaseURL:    "https://labs.example.com",
+		APIBaseURL: "https://labs.example.com/api",
+		Transport:  TransportOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	})
+
+	_, err := client.GetPlay(context.Background(), "play-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transport settings")
+
+	_, err = NewTransport(TransportOptions{ProxyURL: "not a url"})
+	require.Error(t, err)
+}
@@ -97,7 +97,7 @@ func (c *Client) ListTutorials(ctx context.Context, opts *ListTutorialsOptions)
 		}
 	}
 
-	return tutorials, c.GetInto(ctx, "/tutorials", query, nil, &tutorials)
+	return tutorials, c.getCachedInto(ctx, "/tutorials", query, &tutorials)
 }
 
 func (c *Client) ListAuthoredTutorials(ctx context.Context) ([]Tutorial, error) {
@@ -203,7 +203,7 @@ func saveSessionAndGenerateSSHIdentity(
 	}
 
 	if err := cli.Config().Dump(); err != nil {
-		return fmt.Errorf("couldn't save the credentials to the config file: %w", err)
+		return fmt.Errorf("couldn't save the credentials: %w", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package challenge
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
@@ -17,6 +18,7 @@ import (
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/ide"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
 	"github.com/iximiuz/labctl/internal/safety"
 	issh "github.com/iximiuz/labctl/internal/ssh"
 )
@@ -170,6 +172,8 @@ func runStartChallenge(ctx context.Context, cli labcli.CLI, opts *startOptions)
 		return fmt.Errorf("couldn't start solving the challenge: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStart, chal.Play)
+
 	if opts.machine, err = chal.Play.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
@@ -319,10 +323,14 @@ func runStartChallenge(ctx context.Context, cli labcli.CLI, opts *startOptions)
 
 					cli.PrintAux("\r\n\r\nStopping the playground...\r\n")
 
-					if chal, err := cli.Client().StopChallenge(ctx, chal.Name); err != nil {
+					if stopped, err := cli.Client().StopChallenge(ctx, chal.Name); err != nil {
 						cli.PrintErr("Error stopping the challenge: %v\n", err)
-					} else if chal.Play == nil || !chal.Play.IsActive() {
-						cli.PrintAux("Playground stopped.\r\n")
+					} else {
+						ledger.Record(cli, ledger.ActionStop, cmp.Or(stopped.Play, chal.Play))
+
+						if stopped.Play == nil || !stopped.Play.IsActive() {
+							cli.PrintAux("Playground stopped.\r\n")
+						}
 					}
 				}
 
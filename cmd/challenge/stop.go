@@ -1,6 +1,7 @@
 package challenge
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
 )
 
 type stopOptions struct {
@@ -64,10 +66,13 @@ func runStopChallenge(ctx context.Context, cli labcli.CLI, opts *stopOptions) er
 		return nil
 	}
 
-	if _, err = cli.Client().StopChallenge(ctx, opts.challenge); err != nil {
+	stopped, err := cli.Client().StopChallenge(ctx, opts.challenge)
+	if err != nil {
 		return fmt.Errorf("couldn't stop the challenge: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStop, cmp.Or(stopped.Play, chal.Play))
+
 	cli.PrintAux("Challenge attempt has been stopped.\n")
 	return nil
 }
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+func NewCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config <view|get|set|unset|path|validate|get-contexts|use-context|set-context>",
+		Short: "Manage labctl configuration and contexts",
+	}
+
+	cmd.AddCommand(
+		newViewCommand(cli),
+		newGetCommand(cli),
+		newSetCommand(cli),
+		newUnsetCommand(cli),
+		newPathCommand(cli),
+		newValidateCommand(cli),
+		newGetContextsCommand(cli),
+		newUseContextCommand(cli),
+		newSetContextCommand(cli),
+	)
+
+	return cmd
+}
+
+// saveConfig writes the config back, unless the file on disk is broken: the
+// config in effect is a fallback to the defaults then, and dumping it would
+// silently wipe whatever the file had.
+func saveConfig(cfg *config.Config) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("unable to determine home directory: %w", err)
+	}
+
+	if _, err := config.Load(homeDir); err != nil {
+		return labcli.NewStatusError(1,
+			"%s\n\nRefusing to overwrite %s - fix it first (see 'labctl config validate')", err, cfg.FilePath)
+	}
+
+	if err := cfg.Dump(); err != nil {
+		return fmt.Errorf("couldn't save the config: %w", err)
+	}
+
+	return nil
+}
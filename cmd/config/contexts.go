@@ -0,0 +1,285 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+type getContextsOptions struct {
+	output string
+}
+
+func newGetContextsCommand(cli labcli.CLI) *cobra.Command {
+	var opts getContextsOptions
+
+	cmd := &cobra.Command{
+		Use:   "get-contexts [flags]",
+		Short: "List config contexts",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output != "table" && opts.output != "name" {
+				return fmt.Errorf("invalid output format: %s (supported formats: table, name)", opts.output)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runGetContexts(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"table",
+		`Output format: table, name`,
+	)
+
+	return cmd
+}
+
+type contextRow struct {
+	current  bool
+	context  config.Context
+	loggedIn string
+}
+
+func runGetContexts(cli labcli.CLI, opts *getContextsOptions) error {
+	cfg := cli.Config()
+
+	var rows []contextRow
+	for _, name := range cfg.ContextNames() {
+		ctx, _ := cfg.GetContext(name)
+		rows = append(rows, contextRow{
+			current: name == cfg.ActiveContext(),
+			context: ctx,
+		})
+	}
+
+	if opts.output == "name" {
+		for _, row := range rows {
+			cli.PrintOut("%s\n", row.context.Name)
+		}
+		return nil
+	}
+
+	for i := range rows {
+		rows[i].loggedIn = sessionStatus(cfg, rows[i].context.Name)
+	}
+
+	printer := newContextsTablePrinter(cli.OutputStream())
+	defer printer.Flush()
+
+	return printer.Print(rows)
+}
+
+func newContextsTablePrinter(w io.Writer) labcli.Printer[contextRow, []contextRow] {
+	header := []string{
+		"CURRENT",
+		"NAME",
+		"ENDPOINT",
+		"LOGGED IN",
+		"PLAYS DIR",
+		"SSH IDENTITY",
+	}
+
+	rowFunc := func(row contextRow) []string {
+		current := ""
+		if row.current {
+			current = "*"
+		}
+
+		return []string{
+			current,
+			row.context.Name,
+			orDash(row.context.BaseURL),
+			row.loggedIn,
+			orDash(row.context.PlaysDir),
+			orDash(row.context.SSHIdentityFile),
+		}
+	}
+
+	return labcli.NewSliceTablePrinter(w, header, rowFunc)
+}
+
+// sessionStatus tells whether the context is logged in. With a credentials
+// helper, the sessions aren't in the config file, and if the helper can't be
+// asked, it's "unknown".
+func sessionStatus(cfg *config.Config, name string) string {
+	ok, err := cfg.HasSession(name)
+	switch {
+	case err != nil:
+		slog.Debug("Couldn't look up the context's session", "context", name, "error", err.Error())
+		return "unknown"
+	case ok:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func newUseContextCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use-context <name>",
+		Short: "Switch the current config context",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return cli.Config().ContextNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runUseContext(cli, args[0]))
+		},
+	}
+
+	return cmd
+}
+
+func runUseContext(cli labcli.CLI, name string) error {
+	cfg := cli.Config()
+
+	if err := cfg.UseContext(name); err != nil {
+		return labcli.NewStatusError(1,
+			"%s\n\nAvailable contexts: %s", err, strings.Join(cfg.ContextNames(), ", "))
+	}
+
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	cli.PrintAux("Switched to context %q.\n", name)
+	if cfg.SessionID == "" || cfg.AccessToken == "" {
+		cli.PrintAux("The context has no active session - run 'labctl auth login' to sign in.\n")
+	}
+
+	return nil
+}
+
+type setContextOptions struct {
+	endpoint        string
+	playsDir        string
+	sshIdentityFile string
+	use             bool
+}
+
+func newSetContextCommand(cli labcli.CLI) *cobra.Command {
+	var opts setContextOptions
+
+	cmd := &cobra.Command{
+		Use:   "set-context [flags] <name>",
+		Short: "Create a config context or update an existing one",
+		Long: `Create a config context or update an existing one.
+
+Every context has its own session, plays directory, and SSH identity. To sign
+in to a new context, use:
+
+  labctl --context <name> auth login`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runSetContext(cli, args[0], &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&opts.endpoint,
+		"endpoint",
+		"",
+		`iximiuz Labs endpoint URL (e.g., https://labs.iximiuz.com)`,
+	)
+	flags.StringVar(
+		&opts.playsDir,
+		"plays-dir",
+		"",
+		`Directory to keep the context's local playground data in`,
+	)
+	flags.StringVar(
+		&opts.sshIdentityFile,
+		"ssh-identity-file",
+		"",
+		`Path to the context's SSH identity (private key) file`,
+	)
+	flags.BoolVar(
+		&opts.use,
+		"use",
+		false,
+		`Also make the context the current one`,
+	)
+
+	return cmd
+}
+
+func runSetContext(cli labcli.CLI, name string, opts *setContextOptions) error {
+	cfg := cli.Config()
+
+	ctx, exists := cfg.GetContext(name)
+	if !exists {
+		ctx = config.Context{Name: name}
+	}
+
+	if opts.endpoint != "" {
+		endpoint := strings.TrimSuffix(opts.endpoint, "/")
+		if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+			return labcli.NewStatusError(1, "invalid endpoint %q: must be an http(s):// URL", opts.endpoint)
+		}
+
+		// A session is bound to its endpoint.
+		if exists && endpoint != ctx.BaseURL {
+			ctx.SessionID = ""
+			ctx.AccessToken = ""
+		}
+
+		ctx.BaseURL = endpoint
+		ctx.APIBaseURL = endpoint + "/api"
+	}
+	if opts.playsDir != "" {
+		ctx.PlaysDir = opts.playsDir
+	}
+	if opts.sshIdentityFile != "" {
+		ctx.SSHIdentityFile = opts.sshIdentityFile
+	}
+
+	if err := cfg.SetContext(ctx); err != nil {
+		return labcli.NewStatusError(1, "%s", err)
+	}
+
+	if opts.use {
+		if err := cfg.UseContext(name); err != nil {
+			return err
+		}
+	}
+
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	if exists {
+		cli.PrintAux("Context %q updated.\n", name)
+	} else {
+		cli.PrintAux("Context %q created.\n", name)
+	}
+	if opts.use {
+		cli.PrintAux("Switched to context %q.\n", name)
+	}
+
+	return nil
+}
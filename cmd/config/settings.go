@@ -0,0 +1,209 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+type viewOptions struct {
+	raw bool
+}
+
+func newViewCommand(cli labcli.CLI) *cobra.Command {
+	var opts viewOptions
+
+	cmd := &cobra.Command{
+		Use:   "view [flags]",
+		Short: "Show the settings in effect (secrets are masked)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runView(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.BoolVar(
+		&opts.raw,
+		"raw",
+		false,
+		`Show secrets as is`,
+	)
+
+	return cmd
+}
+
+func runView(cli labcli.CLI, opts *viewOptions) error {
+	cfg := cli.Config()
+
+	// A mapping node keeps the settings in their natural order and the
+	// encoder takes care of quoting.
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range config.Settings() {
+		value, _ := cfg.Get(s.Key)
+		if !opts.raw {
+			value = s.Masked(value)
+		}
+
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+		)
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("couldn't render the config: %w", err)
+	}
+
+	cli.PrintAux("# Context: %s\n", cfg.ActiveContext())
+	cli.PrintOut("%s", out)
+
+	return nil
+}
+
+func newGetCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "get <key>",
+		Short:             "Print the value of a setting",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettingKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := cli.Config().Get(args[0])
+			if err != nil {
+				return labcli.NewStatusError(1, "%s", err)
+			}
+
+			cli.PrintOut("%s\n", value)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newSetCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "set <key> <value>",
+		Short:             "Change a setting",
+		Long:              "Change a setting.\n\nAvailable settings:\n\n" + describeSettings(),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSettingKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runSet(cli, args[0], args[1]))
+		},
+	}
+
+	return cmd
+}
+
+func runSet(cli labcli.CLI, key, value string) error {
+	cfg := cli.Config()
+
+	if err := cfg.Set(key, value); err != nil {
+		return labcli.NewStatusError(1, "%s", err)
+	}
+
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	if key == "base_url" && cfg.SessionID == "" {
+		cli.PrintAux("Endpoint changed - run 'labctl auth login' to sign in.\n")
+	}
+
+	return nil
+}
+
+func newUnsetCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "unset <key>",
+		Short:             "Restore the default value of a setting",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettingKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := cli.Config()
+
+			if err := cfg.Unset(args[0]); err != nil {
+				return labcli.NewStatusError(1, "%s", err)
+			}
+
+			return labcli.WrapStatusError(saveConfig(cfg))
+		},
+	}
+
+	return cmd
+}
+
+func newPathCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the path to the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.PrintOut("%s\n", cli.Config().FilePath)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newValidateCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for errors",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runValidate(cli))
+		},
+	}
+
+	return cmd
+}
+
+func runValidate(cli labcli.CLI) error {
+	// The config in effect may be a fallback to the defaults (if the file
+	// is broken), so the file is checked on its own.
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("unable to determine home directory: %w", err)
+	}
+
+	path := config.ConfigFilePath(homeDir)
+
+	errs := config.Validate(homeDir)
+	if len(errs) == 0 {
+		cli.PrintAux("%s is valid.\n", path)
+		return nil
+	}
+
+	var lines []string
+	for _, err := range errs {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return labcli.NewStatusError(1, "%s has %d problem(s):\n%s", path, len(errs), strings.Join(lines, "\n"))
+}
+
+func describeSettings() string {
+	var sb strings.Builder
+	for _, s := range config.Settings() {
+		fmt.Fprintf(&sb, "  %-32s %s\n", s.Key, s.Description)
+	}
+	return sb.String()
+}
+
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.SettingKeys(), cobra.ShellCompDirectiveNoFileComp
+}
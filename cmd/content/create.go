@@ -16,6 +16,7 @@ import (
 	"github.com/iximiuz/labctl/internal/browser"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/schema"
 )
 
 type createOptions struct {
@@ -24,6 +25,8 @@ type createOptions struct {
 	noOpen bool
 	quiet  bool
 
+	schemaModeline bool
+
 	DirOptions
 }
 
@@ -61,6 +64,12 @@ func newCreateCommand(cli labcli.CLI) *cobra.Command {
 		false,
 		`Only print the content name`,
 	)
+	flags.BoolVar(
+		&opts.schemaModeline,
+		"schema-modeline",
+		false,
+		`Point the content files at the labctl JSON Schemas for completion and inline errors in editors using yaml-language-server: playground manifests (YAML) directly, and markdown front matter with a markdown-aware extension only`,
+	)
 
 	opts.AddDirFlag(flags, "Local directory with content files (default: $CWD/<content-name>)")
 
@@ -158,11 +167,76 @@ func runCreateContent(ctx context.Context, cli labcli.CLI, opts *createOptions)
 		}
 	}
 
+	if opts.schemaModeline {
+		if err := addSchemaModelines(cli, dir, files); err != nil {
+			cli.PrintErr("WARNING: Couldn't add the schema modelines: %v\n", err)
+		}
+	}
+
 	cli.PrintAux("Happy authoring!\n")
 	cli.PrintOut("%s\n", cont.GetName())
 	return nil
 }
 
+// addSchemaModelines points the YAML playground manifests and the markdown
+// front matter at the schemas. Note that yaml-language-server itself only
+// looks at YAML files - the front matter modelines take effect only in editors
+// with a markdown-aware extension on top of it.
+func addSchemaModelines(cli labcli.CLI, dir string, files []string) error {
+	manifestSchema, err := schema.Install(cli.Config().SchemasDir(), schema.PlaygroundManifestFile)
+	if err != nil {
+		return err
+	}
+
+	frontMatterSchema, err := schema.Install(cli.Config().SchemasDir(), schema.ContentFrontMatterFile)
+	if err != nil {
+		return err
+	}
+
+	var frontMatter bool
+
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+
+		var markdown bool
+		var schemaPath string
+		switch filepath.Ext(file) {
+		case ".md":
+			markdown, schemaPath = true, frontMatterSchema
+		case ".yaml", ".yml":
+			schemaPath = manifestSchema
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if !markdown && !playgroundManifestPattern.Match(data) {
+			continue
+		}
+
+		data, ok := schema.AddModeline(data, markdown, schemaPath)
+		if !ok {
+			continue
+		}
+
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+
+		frontMatter = frontMatter || markdown
+	}
+
+	if frontMatter {
+		cli.PrintAux("Note: the front matter schema modelines need an editor extension that validates YAML inside markdown.\n")
+	}
+
+	return nil
+}
+
 func createChallenge(ctx context.Context, cli labcli.CLI, opts *createOptions) (content.Content, error) {
 	ch, err := cli.Client().CreateChallenge(ctx, api.CreateChallengeRequest{
 		Name: opts.name,
@@ -296,10 +370,11 @@ func maybeCreateAuthorProfile(ctx context.Context, cli labcli.CLI) error {
 }
 
 var (
-	authorDisplayNamePattern = regexp.MustCompile(`^[\p{L}0-9 -]+$`)
-	authorNamePattern        = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
-	nonSlugRunePattern       = regexp.MustCompile(`[^a-z0-9-]+`)
-	repeatedDashPattern      = regexp.MustCompile(`-+`)
+	authorDisplayNamePattern  = regexp.MustCompile(`^[\p{L}0-9 -]+$`)
+	authorNamePattern         = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+	nonSlugRunePattern        = regexp.MustCompile(`[^a-z0-9-]+`)
+	repeatedDashPattern       = regexp.MustCompile(`-+`)
+	playgroundManifestPattern = regexp.MustCompile(`(?m)^kind:\s*["']?playground["']?\s*$`)
 )
 
 func validateAuthorDisplayName(v string) error {
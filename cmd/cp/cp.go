@@ -10,6 +10,7 @@ import (
 
 	"github.com/iximiuz/labctl/cmd/sshproxy"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 const example = `  # Copy a file from local machine to playground
@@ -23,6 +24,9 @@ const example = `  # Copy a file from local machine to playground
 
   # Copy a directory from the playground to local machine
   labctl cp 65e78a64366c2b0cf9ddc34c:~/some/dir ./some/dir
+
+  # Refer to the playground by its alias or title instead of the ID
+  labctl cp myk3s:~/some/file ./some/file
 `
 
 type Direction string
@@ -73,6 +77,12 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 				opts.localPath = args[0]
 			}
 
+			playID, err := playref.Resolve(cmd.Context(), cli, opts.playID)
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
+
 			return labcli.WrapStatusError(runCopy(cmd.Context(), cli, &opts))
 		},
 	}
@@ -151,3 +161,24 @@ func runCopy(ctx context.Context, cli labcli.CLI, opts *options) error {
 		},
 	})
 }
+
+// CopyToPlay copies a local file or directory to the playground machine.
+func CopyToPlay(
+	ctx context.Context,
+	cli labcli.CLI,
+	playID string,
+	machine string,
+	user string,
+	localPath string,
+	remotePath string,
+) error {
+	return runCopy(ctx, cli, &options{
+		machine:    machine,
+		user:       user,
+		playID:     playID,
+		localPath:  localPath,
+		remotePath: remotePath,
+		recursive:  true,
+		direction:  DirectionLocalToRemote,
+	})
+}
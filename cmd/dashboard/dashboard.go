@@ -0,0 +1,91 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+const defaultRefreshInterval = 10 * time.Second
+
+type options struct {
+	refresh time.Duration
+
+	// The root flags (--endpoint, --no-cache, etc.) the dashboard was
+	// started with - forwarded to the labctl commands it runs.
+	rootFlags []string
+}
+
+func NewCommand(cli labcli.CLI) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "dashboard [flags]",
+		Short: "Browse and manage your playgrounds, their machines, and tasks in a full-screen terminal UI",
+		Long: `Browse and manage your playgrounds, their machines, and tasks in a full-screen terminal UI.
+
+The list of playgrounds is refreshed periodically, and the playground being looked at
+is updated live. Press ? in the dashboard to see all keybindings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.refresh <= 0 {
+				return labcli.NewStatusError(1, "--refresh must be positive")
+			}
+
+			cmd.Root().PersistentFlags().VisitAll(func(f *pflag.Flag) {
+				// The active context is always forwarded by the model.
+				// The HAR trace isn't: the child would rewrite the same
+				// file as the dashboard, clobbering each other's entries.
+				if f.Changed && f.Name != "context" && f.Name != "trace-http" {
+					opts.rootFlags = append(opts.rootFlags, "--"+f.Name+"="+f.Value.String())
+				}
+			})
+
+			return labcli.WrapStatusError(runDashboard(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.DurationVar(
+		&opts.refresh,
+		"refresh",
+		defaultRefreshInterval,
+		`How often to refresh the list of playgrounds`,
+	)
+
+	return cmd
+}
+
+func runDashboard(ctx context.Context, cli labcli.CLI, opts *options) error {
+	if !cli.InputStream().IsTerminal() || !cli.OutputStream().IsTerminal() {
+		return labcli.NewStatusError(1, "the dashboard requires an interactive terminal")
+	}
+
+	// ssh and port-forward take over the terminal, so they are run as
+	// separate labctl processes while the dashboard is suspended.
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("couldn't locate the labctl executable: %w", err)
+	}
+
+	m := newModel(ctx, cli, opts, self)
+	defer m.disconnect()
+
+	// Bubble Tea needs the actual stdin/stdout files to manage the terminal,
+	// hence no WithInput/WithOutput options.
+	p := tea.NewProgram(m, tea.WithContext(ctx), tea.WithAltScreen())
+
+	if _, err := p.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("dashboard failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,700 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/playref"
+)
+
+type screen int
+
+const (
+	screenPlays screen = iota
+	screenPlay
+	screenTask
+)
+
+type pane int
+
+const (
+	paneMachines pane = iota
+	paneTasks
+)
+
+type model struct {
+	ctx  context.Context
+	cli  labcli.CLI
+	opts *options
+	self string
+
+	width  int
+	height int
+
+	screen screen
+	help   bool
+
+	plays   []*api.Play
+	cursor  int
+	loading bool
+
+	// The play being looked at. It's kept up to date over the play
+	// connection while the play is active.
+	play          *api.Play
+	tasks         []api.PlayTaskDetails
+	pane          pane
+	machineCursor int
+	taskCursor    int
+	output        viewport.Model
+
+	conn     *api.PlayConn
+	connStop chan struct{}
+
+	prompt *prompt
+	status string
+	err    error
+}
+
+// prompt asks for a confirmation (input is nil) or a value at the bottom of
+// the screen.
+type prompt struct {
+	title    string
+	input    *textinput.Model
+	onSubmit func(value string) tea.Cmd
+}
+
+type (
+	playsMsg struct {
+		plays []*api.Play
+		err   error
+	}
+
+	playMsg struct {
+		play *api.Play
+		err  error
+	}
+
+	tasksMsg struct {
+		playID string
+		tasks  []api.PlayTaskDetails
+		err    error
+	}
+
+	connMsg struct {
+		playID  string
+		conn    *api.PlayConn
+		stop    chan struct{}
+		updates <-chan api.PlayConnMessage
+		err     error
+	}
+
+	updateMsg struct {
+		playID  string
+		msg     api.PlayConnMessage
+		updates <-chan api.PlayConnMessage
+	}
+
+	connClosedMsg struct {
+		playID string
+	}
+
+	actionMsg struct {
+		playID string
+		status string
+		err    error
+	}
+
+	refreshMsg struct{}
+)
+
+func newModel(ctx context.Context, cli labcli.CLI, opts *options, self string) *model {
+	return &model{
+		ctx:     ctx,
+		cli:     cli,
+		opts:    opts,
+		self:    self,
+		loading: true,
+		output:  viewport.New(0, 0),
+	}
+}
+
+func (m *model) Init() tea.Cmd {
+	return tea.Batch(m.loadPlays(), m.scheduleRefresh())
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resizeOutput()
+		return m, nil
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+
+	case refreshMsg:
+		return m, tea.Batch(m.loadPlays(), m.scheduleRefresh())
+
+	case playsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.plays = msg.plays
+		m.cursor = clamp(m.cursor, len(m.plays))
+		return m, nil
+
+	case playMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if m.play == nil || m.play.ID != msg.play.ID {
+			return m, nil
+		}
+		m.play = msg.play
+		m.machineCursor = clamp(m.machineCursor, len(m.play.Machines))
+
+		var cmd tea.Cmd
+		if m.conn == nil && m.play.IsActive() {
+			cmd = m.connect(m.play)
+		}
+		return m, tea.Batch(cmd, m.loadTasks(m.play.ID))
+
+	case tasksMsg:
+		if m.play == nil || m.play.ID != msg.playID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.tasks = msg.tasks
+		m.taskCursor = clamp(m.taskCursor, len(m.tasks))
+		m.updateOutput()
+		return m, nil
+
+	case connMsg:
+		if m.play == nil || m.play.ID != msg.playID {
+			if msg.conn != nil {
+				close(msg.stop)
+				msg.conn.Close()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.err = fmt.Errorf("live updates are unavailable: %w", msg.err)
+			return m, nil
+		}
+		m.conn, m.connStop = msg.conn, msg.stop
+		return m, listen(msg.playID, msg.updates)
+
+	case updateMsg:
+		if m.play == nil || m.play.ID != msg.playID {
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		switch msg.msg.Kind {
+		case "task":
+			if m.play.Tasks == nil {
+				m.play.Tasks = map[string]api.PlayTask{}
+			}
+			m.play.Tasks[msg.msg.Task.Name] = msg.msg.Task
+			cmd = m.loadTasks(m.play.ID)
+		case "status":
+			if msg.msg.Status != nil {
+				m.play.Status = msg.msg.Status
+			}
+		}
+		return m, tea.Batch(cmd, listen(msg.playID, msg.updates))
+
+	case connClosedMsg:
+		if m.play != nil && m.play.ID == msg.playID {
+			m.disconnect()
+		}
+		return m, nil
+
+	case actionMsg:
+		m.status, m.err = msg.status, msg.err
+
+		cmds := []tea.Cmd{m.loadPlays()}
+		if m.play != nil && m.play.ID == msg.playID {
+			cmds = append(cmds, m.loadPlay(msg.playID))
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	if m.prompt != nil && m.prompt.input != nil {
+		input, cmd := m.prompt.input.Update(msg)
+		m.prompt.input = &input
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() == "ctrl+c" {
+		return tea.Quit
+	}
+
+	if m.prompt != nil {
+		return m.handlePromptKey(msg)
+	}
+
+	if m.help {
+		m.help = false
+		return nil
+	}
+
+	m.status, m.err = "", nil
+
+	switch msg.String() {
+	case "?":
+		m.help = true
+		return nil
+
+	case "q":
+		if m.screen == screenPlays {
+			return tea.Quit
+		}
+		m.back()
+		return nil
+
+	case "esc", "backspace":
+		m.back()
+		return nil
+	}
+
+	switch m.screen {
+	case screenPlays:
+		return m.handlePlaysKey(msg)
+	case screenPlay:
+		return m.handlePlayKey(msg)
+	default:
+		var cmd tea.Cmd
+		m.output, cmd = m.output.Update(msg)
+		return cmd
+	}
+}
+
+func (m *model) handlePromptKey(msg tea.KeyMsg) tea.Cmd {
+	p := m.prompt
+
+	if p.input == nil {
+		switch msg.String() {
+		case "y", "Y":
+			m.prompt = nil
+			return p.onSubmit("")
+		case "n", "N", "esc", "q":
+			m.prompt = nil
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		m.prompt = nil
+		return p.onSubmit(strings.TrimSpace(p.input.Value()))
+	case "esc":
+		m.prompt = nil
+		return nil
+	}
+
+	input, cmd := p.input.Update(msg)
+	p.input = &input
+	return cmd
+}
+
+func (m *model) handlePlaysKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		m.cursor = clamp(m.cursor-1, len(m.plays))
+	case "down", "j":
+		m.cursor = clamp(m.cursor+1, len(m.plays))
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = clamp(len(m.plays)-1, len(m.plays))
+	case "r":
+		m.loading = true
+		return m.loadPlays()
+	case "enter", "right", "l":
+		if play := m.selectedPlay(); play != nil {
+			return m.open(play)
+		}
+	default:
+		return m.handleActionKey(msg, m.selectedPlay(), "")
+	}
+	return nil
+}
+
+func (m *model) handlePlayKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "tab":
+		if m.pane == paneMachines {
+			m.pane = paneTasks
+		} else {
+			m.pane = paneMachines
+		}
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "r":
+		return m.loadPlay(m.play.ID)
+	case "enter", "right", "l":
+		if m.pane == paneTasks && len(m.tasks) > 0 {
+			m.screen = screenTask
+			m.updateOutput()
+			m.output.GotoTop()
+		}
+	default:
+		return m.handleActionKey(msg, m.play, m.selectedMachine())
+	}
+	return nil
+}
+
+// handleActionKey handles the keys that act on a play (and, optionally, one
+// of its machines) on both the list and the play screens.
+func (m *model) handleActionKey(msg tea.KeyMsg, play *api.Play, machine string) tea.Cmd {
+	if play == nil {
+		return nil
+	}
+
+	switch msg.String() {
+	case "s":
+		return m.execLabctl(withMachine([]string{"ssh", play.ID}, machine)...)
+
+	case "p":
+		m.ask(fmt.Sprintf("Forward port (e.g., 8080 or 8080:80) of playground %s:", playName(play)), func(spec string) tea.Cmd {
+			if spec == "" {
+				return nil
+			}
+			return m.execLabctl(withMachine([]string{"port-forward", play.ID, "-L", spec}, machine)...)
+		})
+
+	case "e":
+		m.ask(fmt.Sprintf("Expose port of playground %s:", playName(play)), func(port string) tea.Cmd {
+			if port == "" {
+				return nil
+			}
+			return m.expose(play, machine, port)
+		})
+
+	case "S":
+		m.confirm(fmt.Sprintf("Stop playground %s?", playName(play)), func(string) tea.Cmd {
+			return m.act(play.ID, "stopped", func(ctx context.Context, client *api.Client) error {
+				stopped, err := client.StopPlay(ctx, play.ID)
+				if err == nil {
+					ledger.Record(m.cli, ledger.ActionStop, stopped)
+				}
+				return err
+			})
+		})
+
+	case "R":
+		m.confirm(fmt.Sprintf("Restart playground %s?", playName(play)), func(string) tea.Cmd {
+			return m.act(play.ID, "restarted", func(ctx context.Context, client *api.Client) error {
+				restarted, err := client.RestartPlay(ctx, play.ID)
+				if err == nil {
+					ledger.Record(m.cli, ledger.ActionRestart, restarted)
+				}
+				return err
+			})
+		})
+
+	case "D":
+		m.confirm(fmt.Sprintf("Destroy playground %s? All its data will be lost.", playName(play)), func(string) tea.Cmd {
+			return m.act(play.ID, "destroyed", func(ctx context.Context, client *api.Client) error {
+				err := client.DestroyPlay(ctx, play.ID)
+				if err == nil {
+					ledger.Record(m.cli, ledger.ActionDestroy, play)
+				}
+				return err
+			})
+		})
+	}
+
+	return nil
+}
+
+func (m *model) confirm(title string, onSubmit func(string) tea.Cmd) {
+	m.prompt = &prompt{title: title + " (y/n)", onSubmit: onSubmit}
+}
+
+func (m *model) ask(title string, onSubmit func(string) tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 32
+	input.Focus()
+
+	m.prompt = &prompt{title: title, input: &input, onSubmit: onSubmit}
+}
+
+func (m *model) back() {
+	switch m.screen {
+	case screenTask:
+		m.screen = screenPlay
+	case screenPlay:
+		m.disconnect()
+		m.screen = screenPlays
+		m.play, m.tasks = nil, nil
+	}
+}
+
+func (m *model) open(play *api.Play) tea.Cmd {
+	m.disconnect()
+
+	m.screen = screenPlay
+	m.play = play
+	m.tasks = nil
+	m.pane = paneMachines
+	m.machineCursor, m.taskCursor = 0, 0
+
+	// The listed play may be stale, so the connection is started once the
+	// fresh one arrives.
+	return m.loadPlay(play.ID)
+}
+
+func (m *model) disconnect() {
+	if m.conn == nil {
+		return
+	}
+
+	close(m.connStop)
+	m.conn.Close()
+	m.conn, m.connStop = nil, nil
+}
+
+func (m *model) moveCursor(delta int) {
+	if m.pane == paneMachines {
+		m.machineCursor = clamp(m.machineCursor+delta, len(m.play.Machines))
+	} else {
+		m.taskCursor = clamp(m.taskCursor+delta, len(m.tasks))
+	}
+}
+
+func (m *model) selectedPlay() *api.Play {
+	if len(m.plays) == 0 {
+		return nil
+	}
+	return m.plays[m.cursor]
+}
+
+func (m *model) selectedMachine() string {
+	if m.play == nil || len(m.play.Machines) == 0 {
+		return ""
+	}
+	return m.play.Machines[m.machineCursor].Name
+}
+
+func (m *model) selectedTask() *api.PlayTaskDetails {
+	if len(m.tasks) == 0 {
+		return nil
+	}
+	return &m.tasks[m.taskCursor]
+}
+
+func (m *model) loadPlays() tea.Cmd {
+	ctx, client := m.ctx, m.cli.Client()
+	return func() tea.Msg {
+		plays, err := playref.List(ctx, client)
+		return playsMsg{plays: plays, err: err}
+	}
+}
+
+func (m *model) loadPlay(playID string) tea.Cmd {
+	ctx, client := m.ctx, m.cli.Client()
+	return func() tea.Msg {
+		play, err := client.GetPlay(ctx, playID)
+		if err != nil {
+			return playMsg{err: fmt.Errorf("couldn't get playground: %w", err)}
+		}
+		return playMsg{play: play}
+	}
+}
+
+func (m *model) loadTasks(playID string) tea.Cmd {
+	ctx, client := m.ctx, m.cli.Client()
+	return func() tea.Msg {
+		tasks, err := client.GetPlayTasks(ctx, playID, nil)
+		if err != nil {
+			err = fmt.Errorf("couldn't get playground tasks: %w", err)
+		}
+		return tasksMsg{playID: playID, tasks: tasks, err: err}
+	}
+}
+
+func (m *model) scheduleRefresh() tea.Cmd {
+	return tea.Tick(m.opts.refresh, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+// connect starts the play connection and relays its messages to the model
+// until the connection is closed with disconnect.
+func (m *model) connect(play *api.Play) tea.Cmd {
+	ctx, client, origin := m.ctx, m.cli.Client(), m.cli.Config().WebSocketOrigin()
+
+	// The connection folds the messages into its own copy of the play.
+	snapshot := *play
+	snapshot.Tasks = maps.Clone(play.Tasks)
+	if snapshot.Tasks == nil {
+		snapshot.Tasks = map[string]api.PlayTask{}
+	}
+
+	return func() tea.Msg {
+		conn := api.NewPlayConn(ctx, &snapshot, client, origin)
+		if err := conn.Start(); err != nil {
+			return connMsg{playID: play.ID, err: err}
+		}
+
+		stop := make(chan struct{})
+		updates := make(chan api.PlayConnMessage)
+
+		go func() {
+			defer close(updates)
+
+			_ = conn.Watch(func(msg api.PlayConnMessage) error {
+				select {
+				case updates <- msg:
+					return nil
+				case <-stop:
+					return context.Canceled
+				}
+			})
+		}()
+
+		return connMsg{playID: play.ID, conn: conn, stop: stop, updates: updates}
+	}
+}
+
+func listen(playID string, updates <-chan api.PlayConnMessage) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return connClosedMsg{playID: playID}
+		}
+		return updateMsg{playID: playID, msg: msg, updates: updates}
+	}
+}
+
+func (m *model) act(playID, past string, fn func(context.Context, *api.Client) error) tea.Cmd {
+	ctx, client := m.ctx, m.cli.Client()
+
+	m.status = "Working..."
+	return func() tea.Msg {
+		if err := fn(ctx, client); err != nil {
+			return actionMsg{playID: playID, err: err}
+		}
+		return actionMsg{playID: playID, status: fmt.Sprintf("Playground %s %s.", playID, past)}
+	}
+}
+
+func (m *model) expose(play *api.Play, machine, port string) tea.Cmd {
+	ctx, client := m.ctx, m.cli.Client()
+
+	number, err := strconv.Atoi(port)
+	if err != nil || number <= 0 || number > 65535 {
+		m.err = fmt.Errorf("invalid port number: %s", port)
+		return nil
+	}
+
+	if machine == "" && len(play.Machines) > 0 {
+		machine = play.Machines[0].Name
+	}
+
+	m.status = "Working..."
+	return func() tea.Msg {
+		resp, err := client.ExposePort(ctx, play.ID, api.ExposePortRequest{
+			Machine: machine,
+			Number:  number,
+			Access:  api.AccessPrivate,
+		})
+		if err != nil {
+			return actionMsg{playID: play.ID, err: fmt.Errorf("couldn't expose port %d: %w", number, err)}
+		}
+		return actionMsg{playID: play.ID, status: fmt.Sprintf("Port %s:%d is exposed at %s", machine, number, resp.URL)}
+	}
+}
+
+// execLabctl suspends the dashboard and runs another labctl command in the
+// terminal (in the same config context and with the same root flags).
+func (m *model) execLabctl(args ...string) tea.Cmd {
+	cmdArgs := append([]string{"--context", m.cli.Config().ActiveContext()}, m.opts.rootFlags...)
+	cmdArgs = append(cmdArgs, args...)
+
+	return tea.ExecProcess(exec.CommandContext(m.ctx, m.self, cmdArgs...), func(err error) tea.Msg {
+		var status string
+		if err == nil {
+			status = fmt.Sprintf("'labctl %s' finished.", strings.Join(args, " "))
+		}
+		return actionMsg{status: status, err: err}
+	})
+}
+
+// updateOutput renders the selected task's output into the viewport.
+func (m *model) updateOutput() {
+	task := m.selectedTask()
+	if task == nil {
+		m.output.SetContent("")
+		return
+	}
+
+	var b strings.Builder
+	if task.Run != "" {
+		fmt.Fprintf(&b, "$ %s\n\n", task.Run)
+	}
+	if task.Stdout != "" {
+		b.WriteString(task.Stdout)
+		if !strings.HasSuffix(task.Stdout, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	if task.Stderr != "" {
+		b.WriteString("\n--- stderr ---\n")
+		b.WriteString(task.Stderr)
+	}
+	if b.Len() == 0 {
+		b.WriteString("(no output)")
+	}
+
+	m.output.SetContent(b.String())
+}
+
+func (m *model) resizeOutput() {
+	m.output.Width = m.width
+	m.output.Height = max(m.height-taskHeaderLines-footerLines, 1)
+}
+
+func withMachine(args []string, machine string) []string {
+	if machine == "" {
+		return args
+	}
+	return append(args, "--machine", machine)
+}
+
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
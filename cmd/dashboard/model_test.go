@@ -0,0 +1,174 @@
+package dashboard
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/api/apitest"
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+func keys(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func newTestModel(t *testing.T) (*model, *apitest.Server, []*api.Play) {
+	t.Helper()
+
+	s := apitest.NewServer()
+	t.Cleanup(s.Close)
+
+	s.AddPlayground(api.Playground{
+		Name:     "k3s",
+		Machines: []api.PlaygroundMachine{{Name: "cplane-01"}, {Name: "node-01"}},
+		InitTasks: map[string]api.InitTask{
+			"init_cluster": {Name: "init_cluster", Machine: "cplane-01", Init: true, Run: "k3s-init"},
+		},
+	})
+
+	cli := labcli.NewCLI(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard, "test")
+	cli.SetConfig(config.Default(t.TempDir()))
+	cli.SetClient(s.Client())
+
+	var plays []*api.Play
+	for range 2 {
+		play, err := s.Client().CreatePlay(context.Background(), api.CreatePlayRequest{Playground: "k3s"})
+		require.NoError(t, err)
+		plays = append(plays, play)
+	}
+
+	m := newModel(context.Background(), cli, &options{refresh: time.Minute}, "labctl")
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m.Update(m.loadPlays()())
+
+	return m, s, plays
+}
+
+func TestPlaysScreen(t *testing.T) {
+	m, s, plays := newTestModel(t)
+
+	require.Len(t, m.plays, 2)
+	assert.False(t, m.loading)
+	assert.Contains(t, m.View(), plays[0].ID)
+
+	m.Update(keys("j"))
+	m.Update(keys("j"))
+	assert.Equal(t, 1, m.cursor)
+	m.Update(keys("k"))
+	assert.Equal(t, 0, m.cursor)
+
+	// Destructive actions need a confirmation.
+	target := m.selectedPlay().ID
+
+	m.Update(keys("D"))
+	require.NotNil(t, m.prompt)
+	assert.Contains(t, m.View(), "Destroy playground")
+	m.Update(keys("n"))
+	assert.Nil(t, m.prompt)
+
+	m.Update(keys("D"))
+	_, cmd := m.Update(keys("y"))
+	require.NotNil(t, cmd)
+
+	msg := cmd()
+	require.IsType(t, actionMsg{}, msg)
+	m.Update(msg)
+	assert.NoError(t, m.err)
+	assert.Contains(t, m.status, "destroyed")
+
+	play, ok := s.Play(target)
+	require.True(t, ok)
+	assert.True(t, play.StateIs(api.StateDestroyed))
+}
+
+func TestPlayScreen(t *testing.T) {
+	m, _, _ := newTestModel(t)
+	selected := m.selectedPlay()
+
+	_, cmd := m.Update(keys("enter"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, screenPlay, m.screen)
+
+	m.Update(cmd())
+	m.Update(m.loadTasks(selected.ID)())
+	require.Len(t, m.tasks, 1)
+
+	view := m.View()
+	assert.Contains(t, view, "cplane-01")
+	assert.Contains(t, view, "init_cluster")
+
+	// Machines are selectable for the actions.
+	m.Update(keys("j"))
+	assert.Equal(t, "node-01", m.selectedMachine())
+
+	// Live updates are folded into the play.
+	m.Update(updateMsg{
+		playID: selected.ID,
+		msg: api.PlayConnMessage{
+			Kind: "task",
+			Task: api.PlayTask{Name: "init_cluster", Init: true, Status: api.PlayTaskStatusFailed},
+		},
+	})
+	assert.Equal(t, api.PlayTaskStatusFailed, m.play.Tasks["init_cluster"].Status)
+
+	// Updates for other plays are ignored.
+	m.Update(updateMsg{playID: "other", msg: api.PlayConnMessage{Kind: "status", Status: &api.PlayStatus{}}})
+	assert.True(t, m.play.StateIs(api.StateRunning))
+
+	m.Update(keys("tab"))
+	m.Update(keys("enter"))
+	assert.Equal(t, screenTask, m.screen)
+	assert.Contains(t, m.View(), "$ k3s-init")
+
+	m.Update(keys("esc"))
+	assert.Equal(t, screenPlay, m.screen)
+	m.Update(keys("esc"))
+	assert.Equal(t, screenPlays, m.screen)
+	assert.Nil(t, m.play)
+
+	// Stale responses don't resurrect the closed play.
+	m.Update(m.loadTasks(selected.ID)())
+	assert.Nil(t, m.tasks)
+}
+
+func TestExposePrompt(t *testing.T) {
+	m, _, _ := newTestModel(t)
+
+	m.Update(keys("e"))
+	require.NotNil(t, m.prompt)
+	require.NotNil(t, m.prompt.input)
+
+	m.Update(keys("8"))
+	m.Update(keys("0"))
+	_, cmd := m.Update(keys("enter"))
+	assert.Nil(t, m.prompt)
+	require.NotNil(t, cmd)
+
+	m.Update(cmd())
+	assert.NoError(t, m.err)
+	assert.Contains(t, m.status, "Port cplane-01:80 is exposed")
+
+	// Invalid ports never reach the API.
+	m.Update(keys("e"))
+	m.Update(keys("x"))
+	_, cmd = m.Update(keys("enter"))
+	assert.Nil(t, cmd)
+	assert.Error(t, m.err)
+}
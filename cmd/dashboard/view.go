@@ -0,0 +1,387 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const (
+	headerLines     = 2
+	footerLines     = 2
+	taskHeaderLines = headerLines + 3
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	sectionStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	statusStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	promptStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220"))
+)
+
+var stateColors = map[api.PlayState]string{
+	api.StateRunning:    "42",  // green
+	api.StateStarting:   "220", // yellow
+	api.StateCreated:    "220",
+	api.StateStopping:   "208", // orange
+	api.StateStopped:    "244", // gray
+	api.StateDestroying: "208",
+	api.StateDestroyed:  "240",
+	api.StateFailed:     "196", // red
+}
+
+const helpText = `Playgrounds
+  up/down, j/k   move the selection
+  enter          show the playground's machines and tasks
+  r              refresh
+
+Playground
+  tab            switch between machines and tasks
+  enter          show the selected task's output
+  esc, q         go back
+
+Actions (on the selected playground and machine)
+  s              SSH into the machine
+  p              forward a port to your machine
+  e              expose a port over HTTP(s)
+  S              stop the playground
+  R              restart the playground
+  D              destroy the playground
+
+  ctrl+c         quit`
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	var body string
+	switch {
+	case m.help:
+		body = helpText
+	case m.screen == screenPlays:
+		body = m.viewPlays()
+	case m.screen == screenPlay:
+		body = m.viewPlay()
+	default:
+		body = m.viewTask()
+	}
+
+	bodyHeight := max(m.height-headerLines-footerLines, 0)
+	lines := strings.Split(body, "\n")
+	if len(lines) > bodyHeight {
+		lines = lines[:bodyHeight]
+	}
+	for len(lines) < bodyHeight {
+		lines = append(lines, "")
+	}
+
+	return m.viewHeader() + "\n" + strings.Join(lines, "\n") + "\n" + m.viewFooter()
+}
+
+func (m *model) viewHeader() string {
+	title := titleStyle.Render("labctl dashboard")
+	context := dimStyle.Render("context: " + m.cli.Config().ActiveContext())
+
+	var location string
+	switch {
+	case m.screen == screenPlays && m.loading:
+		location = "playgrounds (loading...)"
+	case m.screen == screenPlays:
+		location = fmt.Sprintf("playgrounds (%d)", len(m.plays))
+	case m.play != nil:
+		location = "playground " + playName(m.play)
+		if m.conn != nil {
+			location += " " + statusStyle.Render("● live")
+		}
+	}
+
+	return truncate(title+"  "+location+"  "+context, m.width) + "\n"
+}
+
+func (m *model) viewFooter() string {
+	var status string
+	switch {
+	case m.prompt != nil && m.prompt.input != nil:
+		status = promptStyle.Render(m.prompt.title) + " " + m.prompt.input.View()
+	case m.prompt != nil:
+		status = promptStyle.Render(m.prompt.title)
+	case m.err != nil:
+		status = errorStyle.Render("Error: " + m.err.Error())
+	default:
+		status = statusStyle.Render(m.status)
+	}
+
+	var keys string
+	switch {
+	case m.prompt != nil && m.prompt.input != nil:
+		keys = "enter: submit  esc: cancel"
+	case m.help:
+		keys = "press any key to close the help"
+	case m.screen == screenPlays:
+		keys = "enter: open  s: ssh  p: port-forward  e: expose  S: stop  R: restart  D: destroy  r: refresh  ?: help  q: quit"
+	case m.screen == screenPlay:
+		keys = "tab: machines/tasks  enter: task output  s: ssh  p: port-forward  e: expose  S/R/D: stop/restart/destroy  esc: back"
+	default:
+		keys = "up/down, pgup/pgdown: scroll  esc: back"
+	}
+
+	return truncate(status, m.width) + "\n" + truncate(dimStyle.Render(keys), m.width)
+}
+
+func (m *model) viewPlays() string {
+	if len(m.plays) == 0 {
+		if m.loading {
+			return ""
+		}
+		return dimStyle.Render("No playgrounds yet. Start one with 'labctl playground start <name>'.")
+	}
+
+	header := formatRow([]int{24, 20, 24, 32}, "PLAYGROUND RUN ID", "PLAYGROUND NAME", "TITLE", "STATUS", "CREATED")
+
+	var rows []string
+	for i, play := range m.plays {
+		row := truncate(formatRow([]int{24, 20, 24, 32},
+			play.ID,
+			play.Playground.Name,
+			play.Title,
+			playStatus(play),
+			humanize.Time(safeParseTime(play.CreatedAt)),
+		), m.width)
+
+		switch {
+		case i == m.cursor:
+			row = selectedStyle.Render(padRight(row, m.width))
+		case !play.IsActive():
+			row = dimStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	height := max(m.height-headerLines-footerLines-1, 1)
+	start, end := window(m.cursor, len(rows), height)
+
+	return titleStyle.Render(truncate(header, m.width)) + "\n" + strings.Join(rows[start:end], "\n")
+}
+
+func (m *model) viewPlay() string {
+	play := m.play
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s  %s", titleStyle.Render(play.ID), play.Playground.Name)
+	if play.Title != "" {
+		fmt.Fprintf(&b, "  %q", play.Title)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "State: %s", renderState(play))
+	if play.PageURL != "" {
+		fmt.Fprintf(&b, "  %s", dimStyle.Render(play.PageURL))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(section("MACHINES", m.pane == paneMachines) + "\n")
+	b.WriteString(dimStyle.Render(formatRow([]int{20, 12, 8}, "NAME", "STATE", "READY", "CONDITIONS")) + "\n")
+	for i, machine := range play.Machines {
+		state := string(play.MachineState(machine.Name))
+		if state == "" {
+			state = "-"
+		}
+
+		ready := "no"
+		if play.MachineReady(machine.Name) {
+			ready = "yes"
+		}
+
+		row := truncate(formatRow([]int{20, 12, 8}, machine.Name, state, ready, machineConditions(play, machine.Name)), m.width)
+		if m.pane == paneMachines && i == m.machineCursor {
+			row = selectedStyle.Render(padRight(row, m.width))
+		}
+		b.WriteString(row + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(section("TASKS", m.pane == paneTasks) + "\n")
+	if m.tasks == nil {
+		b.WriteString(dimStyle.Render("Loading...") + "\n")
+		return b.String()
+	}
+	if len(m.tasks) == 0 {
+		b.WriteString(dimStyle.Render("This playground has no tasks.") + "\n")
+		return b.String()
+	}
+
+	b.WriteString(dimStyle.Render(formatRow([]int{32, 20, 12}, "NAME", "MACHINE", "STATUS", "KIND")) + "\n")
+
+	used := strings.Count(b.String(), "\n")
+	height := max(m.height-headerLines-footerLines-used, 1)
+	start, end := window(m.taskCursor, len(m.tasks), height)
+
+	for i := start; i < end; i++ {
+		task := m.tasks[i]
+		row := truncate(formatRow([]int{32, 20, 12}, task.Name, task.Machine, taskStatus(task.Status), taskKind(task)), m.width)
+		if m.pane == paneTasks && i == m.taskCursor {
+			row = selectedStyle.Render(padRight(row, m.width))
+		}
+		b.WriteString(row + "\n")
+	}
+
+	return b.String()
+}
+
+func (m *model) viewTask() string {
+	task := m.selectedTask()
+	if task == nil {
+		return ""
+	}
+
+	details := []string{"status: " + taskStatus(task.Status)}
+	if task.Machine != "" {
+		details = append(details, "machine: "+task.Machine)
+	}
+	if task.Status == api.PlayTaskStatusFailed || task.ExitCode != 0 {
+		details = append(details, fmt.Sprintf("exit code: %d", task.ExitCode))
+	}
+	if task.LastRunAt != "" {
+		details = append(details, "last run: "+humanize.Time(safeParseTime(task.LastRunAt)))
+	}
+
+	return titleStyle.Render(task.Name) + "\n" +
+		dimStyle.Render(truncate(strings.Join(details, "  "), m.width)) + "\n\n" +
+		m.output.View()
+}
+
+func section(title string, focused bool) string {
+	if focused {
+		return sectionStyle.Render("▸ " + title)
+	}
+	return dimStyle.Render("  " + title)
+}
+
+func renderState(play *api.Play) string {
+	color, ok := stateColors[play.State()]
+	if !ok {
+		color = "244"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(playStatus(play))
+}
+
+func machineConditions(play *api.Play, name string) string {
+	if play.Status == nil {
+		return ""
+	}
+
+	var conds []string
+	for _, m := range play.Status.Machines {
+		if m.Name != name {
+			continue
+		}
+		for _, c := range m.Conditions {
+			conds = append(conds, c.Name+"="+c.Status)
+		}
+	}
+	return strings.Join(conds, ", ")
+}
+
+func playName(play *api.Play) string {
+	if play.Title != "" {
+		return fmt.Sprintf("%s (%s)", play.Title, play.ID)
+	}
+	return fmt.Sprintf("%s (%s)", play.Playground.Name, play.ID)
+}
+
+func playStatus(play *api.Play) string {
+	if play.Status == nil || len(play.Status.StateEvents) == 0 {
+		return "UNKNOWN"
+	}
+
+	if play.StateIs(api.StateRunning) {
+		return fmt.Sprintf("RUNNING (expires in %s)",
+			humanize.Time(time.Now().Add(time.Duration(play.ExpiresIn)*time.Millisecond)))
+	}
+
+	return string(play.State())
+}
+
+func taskStatus(status api.PlayTaskStatus) string {
+	switch status {
+	case api.PlayTaskStatusCreated:
+		return "created"
+	case api.PlayTaskStatusBlocked:
+		return "blocked"
+	case api.PlayTaskStatusRunning:
+		return "running"
+	case api.PlayTaskStatusFailed:
+		return "failed"
+	case api.PlayTaskStatusCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+func taskKind(task api.PlayTaskDetails) string {
+	switch {
+	case task.Init:
+		return "init"
+	case task.Helper:
+		return "helper"
+	default:
+		return "regular"
+	}
+}
+
+// formatRow left-aligns the columns to the given widths (the last column
+// takes the rest of the line).
+func formatRow(widths []int, cols ...string) string {
+	var b strings.Builder
+	for i, col := range cols {
+		if i < len(widths) {
+			fmt.Fprintf(&b, "%-*s  ", widths[i], truncate(col, widths[i]))
+		} else {
+			b.WriteString(col)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// window returns the range of n rows to show so that the cursor stays
+// visible.
+func window(cursor, n, height int) (int, int) {
+	if n <= height {
+		return 0, n
+	}
+
+	start := max(cursor-height/2, 0)
+	if start+height > n {
+		start = n - height
+	}
+	return start, start + height
+}
+
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(s)
+}
+
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+func safeParseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
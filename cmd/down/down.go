@@ -0,0 +1,74 @@
+package down
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/labfile"
+	"github.com/iximiuz/labctl/internal/ledger"
+)
+
+type options struct {
+	file string
+}
+
+func NewCommand(cli labcli.CLI) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "down [flags]",
+		Short: `Tear down the playground environment started with 'labctl up'`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runDown(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		labfile.DefaultFileName,
+		`Path to the labfile`,
+	)
+
+	return cmd
+}
+
+func runDown(ctx context.Context, cli labcli.CLI, opts *options) error {
+	lf, err := labfile.Load(opts.file)
+	if err != nil {
+		return err
+	}
+
+	state, err := labfile.LoadState(labfile.StatePath(lf))
+	if err != nil {
+		return err
+	}
+
+	if state.PlayID == "" {
+		cli.PrintAux("Nothing to tear down.\n")
+		return nil
+	}
+
+	// Port forwards and exposed ports go away with the play.
+	if err := cli.Client().DestroyPlay(ctx, state.PlayID); err != nil && !errors.Is(err, api.ErrNotFound) {
+		return fmt.Errorf("couldn't destroy the playground %s: %w", state.PlayID, err)
+	}
+
+	ledger.Record(cli, ledger.ActionDestroy, &api.Play{ID: state.PlayID})
+
+	if err := state.Remove(); err != nil {
+		return err
+	}
+
+	cli.PrintAux("Playground %s has been destroyed.\n", state.PlayID)
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type listOptions struct {
@@ -28,7 +29,10 @@ func NewListCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			playID := args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
 			return labcli.WrapStatusError(runList(cmd.Context(), cli, playID, &opts))
 		},
 	}
@@ -17,6 +17,8 @@ import (
 	"github.com/iximiuz/labctl/internal/browser"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 )
 
@@ -75,7 +77,11 @@ If --remote-port is not specified, the remote port defaults to <local_port>.`,
 
 			var addrArg string
 			if len(args) == 2 {
-				opts.playID = args[0]
+				playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+				if err != nil {
+					return labcli.WrapStatusError(err)
+				}
+				opts.playID = playID
 				addrArg = args[1]
 			} else {
 				addrArg = args[0]
@@ -246,6 +252,8 @@ func startTmpPlayground(ctx context.Context, cli labcli.CLI) (*api.Play, func(),
 		return nil, nil, fmt.Errorf("couldn't create playground: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStart, play)
+
 	cleanup := func() {
 		cli.PrintAux("Destroying temporary playground %s...\n", play.ID)
 		// Use a fresh context so cleanup runs even after ctx cancellation.
@@ -253,7 +261,9 @@ func startTmpPlayground(ctx context.Context, cli labcli.CLI) (*api.Play, func(),
 		defer cancel()
 		if err := cli.Client().DestroyPlay(destroyCtx, play.ID); err != nil {
 			cli.PrintErr("Warning: couldn't destroy temporary playground %s: %v\n", play.ID, err)
+			return
 		}
+		ledger.Record(cli, ledger.ActionDestroy, play)
 	}
 
 	cli.PrintAux("Temporary playground %s is ready\n", play.ID)
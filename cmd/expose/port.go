@@ -11,6 +11,7 @@ import (
 	"github.com/iximiuz/labctl/internal/browser"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type portOptions struct {
@@ -64,7 +65,11 @@ func NewPortCommand(cli labcli.CLI) *cobra.Command {
 					}
 				}
 
-				opts.playID = args[0]
+				playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+				if err != nil {
+					return labcli.WrapStatusError(err)
+				}
+				opts.playID = playID
 				return labcli.WrapStatusError(runPortScanned(cmd.Context(), cli, &opts))
 			}
 
@@ -72,7 +77,11 @@ func NewPortCommand(cli labcli.CLI) *cobra.Command {
 				return fmt.Errorf("exactly 2 arguments (playground ID and port) are required")
 			}
 
-			opts.playID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 			opts.port = args[1]
 
 			return labcli.WrapStatusError(runPort(cmd.Context(), cli, &opts))
@@ -8,6 +8,7 @@ import (
 
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 func NewRemoveCommand(cli labcli.CLI) *cobra.Command {
@@ -18,7 +19,10 @@ func NewRemoveCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(2),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			playID := args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
 			exposeID := args[1]
 			return labcli.WrapStatusError(runRemove(cmd.Context(), cli, playID, exposeID))
 		},
@@ -12,6 +12,7 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type scanOptions struct {
@@ -27,7 +28,11 @@ func NewScanCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runScan(cmd.Context(), cli, args[0], &opts))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runScan(cmd.Context(), cli, playID, &opts))
 		},
 	}
 
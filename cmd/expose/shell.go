@@ -10,6 +10,7 @@ import (
 	"github.com/iximiuz/labctl/internal/browser"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type shellOptions struct {
@@ -36,7 +37,11 @@ func NewShellCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 			return labcli.WrapStatusError(runShell(cmd.Context(), cli, &opts))
 		},
 	}
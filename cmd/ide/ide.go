@@ -14,9 +14,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/config"
 	ideutil "github.com/iximiuz/labctl/internal/ide"
+	"github.com/iximiuz/labctl/internal/keepalive"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 	"github.com/iximiuz/labctl/internal/retry"
 )
@@ -89,6 +93,12 @@ type options struct {
 	repos   []string
 
 	forwardAgent bool
+
+	keepalive bool
+
+	// projectDefaults fills in the flags from the project config once the
+	// play is known (see applyProjectDefaults).
+	projectDefaults func(play *api.Play)
 }
 
 func NewCommand(cli labcli.CLI) *cobra.Command {
@@ -113,12 +123,20 @@ labctl ide code $PLAY_ID --workdir projects --repo https://github.com/foo/bar
 		ValidArgsFunction: ideCompletion(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.ide = args[0]
-			opts.playID = args[1]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[1])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 
 			if !ideutil.IsSupported(opts.ide) {
 				return fmt.Errorf("unsupported IDE %q (supported: %s)", opts.ide, ideutil.SupportedList())
 			}
 
+			opts.projectDefaults = func(play *api.Play) {
+				applyProjectDefaults(cmd, cli.Config().Project, play, &opts)
+			}
+
 			return labcli.WrapStatusError(runIDE(cmd.Context(), cli, &opts))
 		},
 	}
@@ -161,6 +179,7 @@ labctl ide code $PLAY_ID --workdir projects --repo https://github.com/foo/bar
 		false,
 		`INSECURE: Forward the SSH agent to the playground VM to clone repo(s) (use at your own risk)`,
 	)
+	keepalive.AddFlag(flags, &opts.keepalive)
 
 	return cmd
 }
@@ -184,6 +203,17 @@ func ideCompletion(cli labcli.CLI) completion.CompletionFunc {
 	}
 }
 
+func applyProjectDefaults(cmd *cobra.Command, project config.ProjectConfig, play *api.Play, opts *options) {
+	if !labcli.IsProjectPlay(project, play) {
+		return
+	}
+
+	labcli.ProjectDefault(cmd, "machine", &opts.machine, project.Machine)
+	labcli.ProjectDefault(cmd, "user", &opts.user, project.User)
+	labcli.ProjectDefault(cmd, "workdir", &opts.workDir, project.IDE.WorkDir)
+	labcli.ProjectDefaults(cmd, "repo", &opts.repos, project.IDE.Repos)
+}
+
 func runIDE(ctx context.Context, cli labcli.CLI, opts *options) error {
 	if err := ideutil.EnsureInstalled(opts.ide); err != nil {
 		return err
@@ -196,6 +226,10 @@ func runIDE(ctx context.Context, cli labcli.CLI, opts *options) error {
 		return fmt.Errorf("couldn't get playground: %w", err)
 	}
 
+	if opts.projectDefaults != nil {
+		opts.projectDefaults(p)
+	}
+
 	if opts.machine, err = p.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
@@ -203,6 +237,10 @@ func runIDE(ctx context.Context, cli labcli.CLI, opts *options) error {
 		return err
 	}
 
+	if opts.keepalive {
+		defer keepalive.StartSession(ctx, cli, p.ID)()
+	}
+
 	var (
 		localHost  = "127.0.0.1"
 		localPort  = portforward.RandomLocalPort()
@@ -1,9 +1,16 @@
 package ide
 
 import (
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/labcli"
 )
 
 func TestRemotePathIsAbs(t *testing.T) {
@@ -34,3 +41,43 @@ func TestRepoSpecCloneTarget(t *testing.T) {
 	repo = repoSpec{url: "https://github.com/foo/bar", cloneDir: "home/laborant"}
 	assert.Equal(t, "/home/laborant/home/laborant", repo.cloneTarget(baseDir))
 }
+
+func TestApplyProjectDefaults(t *testing.T) {
+	project := config.ProjectConfig{
+		Playground: "k8s-omni",
+		Machine:    "cplane-01",
+		User:       "root",
+		IDE:        config.ProjectIDE{WorkDir: "projects", Repos: []string{"https://github.com/foo/bar"}},
+	}
+	cli := labcli.NewCLI(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard, "test")
+
+	parse := func(args ...string) (*options, func(*api.Play)) {
+		var opts options
+		cmd := NewCommand(cli)
+		require.NoError(t, cmd.ParseFlags(args))
+		return &opts, func(play *api.Play) { applyProjectDefaults(cmd, project, play, &opts) }
+	}
+
+	// A play of another playground doesn't get the project's defaults.
+	opts, apply := parse()
+	apply(&api.Play{Playground: api.Playground{Name: "docker"}})
+	assert.Empty(t, opts.machine)
+	assert.Empty(t, opts.user)
+	assert.Empty(t, opts.workDir)
+	assert.Empty(t, opts.repos)
+
+	opts, apply = parse()
+	apply(&api.Play{Playground: api.Playground{Name: "k8s-omni"}})
+	assert.Equal(t, "cplane-01", opts.machine)
+	assert.Equal(t, "root", opts.user)
+	assert.Equal(t, "projects", opts.workDir)
+	assert.Equal(t, []string{"https://github.com/foo/bar"}, opts.repos)
+
+	// Explicit flags win (the parsed value itself lands in the command's
+	// own options, hence the assignment).
+	opts, apply = parse("--machine", "node-01")
+	opts.machine = "node-01"
+	apply(&api.Play{Playground: api.Playground{Name: "k8s-omni"}})
+	assert.Equal(t, "node-01", opts.machine)
+	assert.Equal(t, "root", opts.user)
+}
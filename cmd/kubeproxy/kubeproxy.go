@@ -17,6 +17,7 @@ import (
 	"github.com/iximiuz/labctl/cmd/sshproxy"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 )
 
@@ -58,7 +59,11 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 			return labcli.WrapStatusError(runKubeProxy(cmd.Context(), cli, &opts))
 		},
 	}
@@ -0,0 +1,143 @@
+package playground
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
+)
+
+const aliasExample = `  # Give a play a short local name...
+  labctl playground alias myk3s 65e78a64366c2b0cf9ddc34c
+
+  # ...and use it instead of the ID
+  labctl ssh myk3s
+  labctl cp myk3s:~/file .
+
+  # List the aliases
+  labctl playground alias
+`
+
+func newAliasCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "alias [<alias> <playground>]",
+		Short:             `Set a local alias for a playground, or list the aliases`,
+		Example:           aliasExample,
+		ValidArgsFunction: completeAliasArgs(cli),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return fmt.Errorf("expected either no arguments or <alias> <playground>, got %d", len(args))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return labcli.WrapStatusError(runListAliases(cli))
+			}
+
+			return labcli.WrapStatusError(runSetAlias(cmd.Context(), cli, args[0], args[1]))
+		},
+	}
+
+	return cmd
+}
+
+func newUnaliasCommand(cli labcli.CLI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unalias <alias>",
+		Short: `Remove a local playground alias`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runUnalias(cli, args[0]))
+		},
+	}
+
+	return cmd
+}
+
+func runListAliases(cli labcli.CLI) error {
+	aliases, err := playref.LoadAliases(playref.AliasesPath(cli.Config().PlaysDir))
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) == 0 {
+		cli.PrintAux("No aliases set. Use 'labctl playground alias <alias> <playground>' to add one.\n")
+		return nil
+	}
+
+	var names []string
+	for name := range aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		cli.PrintOut("%s\t%s\n", name, aliases[name])
+	}
+
+	return nil
+}
+
+func runSetAlias(ctx context.Context, cli labcli.CLI, name string, ref string) error {
+	if err := playref.ValidateAlias(name); err != nil {
+		return labcli.NewStatusError(1, "%s", err)
+	}
+
+	playID, err := playref.Resolve(ctx, cli, ref)
+	if err != nil {
+		return err
+	}
+
+	play, err := cli.Client().GetPlay(ctx, playID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the playground %s: %w", playID, err)
+	}
+
+	path := playref.AliasesPath(cli.Config().PlaysDir)
+
+	aliases, err := playref.LoadAliases(path)
+	if err != nil {
+		return err
+	}
+
+	aliases[name] = play.ID
+	if err := aliases.Save(path); err != nil {
+		return err
+	}
+
+	cli.PrintAux("Alias %s now points to %s playground %s\n", name, play.Playground.Name, play.ID)
+	return nil
+}
+
+func runUnalias(cli labcli.CLI, name string) error {
+	path := playref.AliasesPath(cli.Config().PlaysDir)
+
+	aliases, err := playref.LoadAliases(path)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := aliases[name]; !ok {
+		return labcli.NewStatusError(1, "alias %q not found", name)
+	}
+
+	delete(aliases, name)
+	return aliases.Save(path)
+}
+
+func completeAliasArgs(cli labcli.CLI) completion.CompletionFunc {
+	complete := completion.NonDestroyedPlays(cli)
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, nil, toComplete)
+	}
+}
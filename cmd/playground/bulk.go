@@ -0,0 +1,229 @@
+package playground
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
+)
+
+const defaultBulkConcurrency = 4
+
+// bulkOptions select the plays the --all mode of stop, destroy, persist, and
+// lifetime acts on.
+type bulkOptions struct {
+	all bool
+
+	filter    string
+	states    []string
+	olderThan time.Duration
+
+	force       bool
+	concurrency int
+}
+
+var bulkOnlyFlags = []string{"filter", "state", "older-than", "force", "concurrency"}
+
+func (opts *bulkOptions) addFlags(flags *pflag.FlagSet, verb string) {
+	flags.BoolVar(
+		&opts.all,
+		"all",
+		false,
+		fmt.Sprintf(`%s all playgrounds matching --filter, --state, and --older-than instead of a single one`, verb),
+	)
+	flags.StringVar(
+		&opts.filter,
+		"filter",
+		"",
+		`With --all: select playgrounds by tutorial=<name>, challenge=<name>, course=<name>, or playground=<name>`,
+	)
+	flags.StringSliceVar(
+		&opts.states,
+		"state",
+		nil,
+		fmt.Sprintf(`With --all: select playgrounds in the given state(s) (%s)`, joinPlayStates()),
+	)
+	flags.DurationVar(
+		&opts.olderThan,
+		"older-than",
+		0,
+		`With --all: select playgrounds created longer ago than the given duration (e.g., 2h, 30m)`,
+	)
+	flags.BoolVarP(
+		&opts.force,
+		"force",
+		"f",
+		false,
+		`With --all: don't ask for confirmation`,
+	)
+	flags.IntVar(
+		&opts.concurrency,
+		"concurrency",
+		defaultBulkConcurrency,
+		`With --all: how many playgrounds to process at once`,
+	)
+}
+
+// args picks the positional args validator for the single-play or the bulk
+// mode.
+func (opts *bulkOptions) args(single, bulk cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.all {
+			return bulk(cmd, args)
+		}
+		return single(cmd, args)
+	}
+}
+
+func (opts *bulkOptions) validate(cmd *cobra.Command) error {
+	if !opts.all {
+		for _, name := range bulkOnlyFlags {
+			if cmd.Flags().Changed(name) {
+				return labcli.NewStatusError(1, "--%s can only be used with --all", name)
+			}
+		}
+		return nil
+	}
+
+	if opts.concurrency < 1 {
+		return labcli.NewStatusError(1, "--concurrency must be at least 1")
+	}
+
+	return nil
+}
+
+type bulkAction struct {
+	// verb and past are used in the confirmation prompt and the summary,
+	// e.g., "destroy" and "destroyed".
+	verb string
+	past string
+
+	// eligible tells the plays the action makes sense for apart from the
+	// ones the user has selected.
+	eligible func(*api.Play) bool
+
+	run func(ctx context.Context, play *api.Play) error
+}
+
+type bulkResult struct {
+	play *api.Play
+	err  error
+}
+
+func runBulk(ctx context.Context, cli labcli.CLI, opts *bulkOptions, action bulkAction) error {
+	filter, err := parseFilter(opts.filter)
+	if err != nil {
+		return err
+	}
+
+	filter, err = filter.withSelectors(opts.states, opts.olderThan)
+	if err != nil {
+		return err
+	}
+
+	plays, err := playref.List(ctx, cli.Client())
+	if err != nil {
+		return err
+	}
+
+	var selected []*api.Play
+	for _, play := range plays {
+		if action.eligible(play) && filter.matches(play) {
+			selected = append(selected, play)
+		}
+	}
+
+	if len(selected) == 0 {
+		cli.PrintAux("No playgrounds to %s.\n", action.verb)
+		return nil
+	}
+
+	cli.PrintAux("The following %d playground(s) will be %s:\n\n", len(selected), action.past)
+
+	printer := newListPrinter(cli.AuxStream(), "table")
+	if err := printer.Print(selected); err != nil {
+		return err
+	}
+	printer.Flush()
+	cli.PrintAux("\n")
+
+	if !opts.force && !cli.Confirm(
+		fmt.Sprintf("Are you sure you want to %s %d playground(s)?", action.verb, len(selected)),
+		"Yes", "No",
+	) {
+		return labcli.NewStatusError(0, "Glad you changed your mind!")
+	}
+
+	results := make([]bulkResult, len(selected))
+
+	var g errgroup.Group
+	g.SetLimit(opts.concurrency)
+
+	for i, play := range selected {
+		g.Go(func() error {
+			results[i] = bulkResult{play: play, err: action.run(ctx, play)}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	summary := labcli.NewSliceTablePrinter(
+		cli.OutputStream(),
+		[]string{"PLAYGROUND RUN ID", "PLAYGROUND NAME", "RESULT"},
+		func(r bulkResult) []string {
+			result := action.past
+			if r.err != nil {
+				result = "FAILED: " + r.err.Error()
+			}
+			return []string{r.play.ID, r.play.Playground.Name, result}
+		},
+	)
+	if err := summary.Print(results); err != nil {
+		return err
+	}
+	summary.Flush()
+
+	var failed int
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return labcli.NewStatusError(1, "%d of %d playground(s) couldn't be %s", failed, len(results), action.past)
+	}
+
+	return nil
+}
+
+// waitPlay polls the play until it satisfies the condition - a quieter
+// version of the single-play waits, suitable for running concurrently.
+func waitPlay(ctx context.Context, cli labcli.CLI, playID string, timeout time.Duration, done func(*api.Play) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		play, err := cli.Client().GetPlay(ctx, playID)
+		if err == nil && done(play) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out waiting for the playground")
+			}
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -16,6 +16,8 @@ type createOptions struct {
 	base string
 	file string
 
+	templateOptions
+
 	quiet bool
 }
 
@@ -66,6 +68,7 @@ func newCreateCommand(cli labcli.CLI) *cobra.Command {
 		"",
 		`Path to playground manifest file`,
 	)
+	opts.addFlags(flags)
 
 	return cmd
 }
@@ -94,7 +97,7 @@ func runCreate(ctx context.Context, cli labcli.CLI, opts *createOptions) error {
 			cli.PrintAux("Creating playground from stdin\n")
 		}
 
-		manifest, err = readManifestFile(opts.file)
+		manifest, err = readValidManifestFile(opts.file, &opts.templateOptions)
 		if err != nil {
 			return fmt.Errorf("couldn't read manifest: %w", err)
 		}
@@ -8,8 +8,11 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 const destroyCommandTimeout = 5 * time.Minute
@@ -17,6 +20,8 @@ const destroyCommandTimeout = 5 * time.Minute
 type destroyOptions struct {
 	playID string
 
+	bulk bulkOptions
+
 	quiet bool
 }
 
@@ -26,12 +31,40 @@ func newDestroyCommand(cli labcli.CLI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "destroy [flags] <playground-id>",
 		Short:             `Destroy an active or stopped playground session, completely deleting its data`,
-		Args:              cobra.ExactArgs(1),
+		Example:           `  labctl playground destroy --all --filter playground=k3s --older-than 2h --state STOPPED`,
+		Args:              opts.bulk.args(cobra.ExactArgs(1), cobra.ExactArgs(0)),
 		ValidArgsFunction: completion.NonDestroyedPlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.SetQuiet(opts.quiet)
 
-			opts.playID = args[0]
+			if err := opts.bulk.validate(cmd); err != nil {
+				return err
+			}
+
+			if opts.bulk.all {
+				return labcli.WrapStatusError(runBulk(cmd.Context(), cli, &opts.bulk, bulkAction{
+					verb: "destroy",
+					past: "destroyed",
+					eligible: func(p *api.Play) bool {
+						return !p.StateIs(api.StateDestroyed) && !p.StateIs(api.StateDestroying)
+					},
+					run: func(ctx context.Context, play *api.Play) error {
+						if err := cli.Client().DestroyPlay(ctx, play.ID); err != nil {
+							return err
+						}
+						ledger.Record(cli, ledger.ActionDestroy, play)
+						return waitPlay(ctx, cli, play.ID, destroyCommandTimeout, func(p *api.Play) bool {
+							return p.StateIs(api.StateDestroyed)
+						})
+					},
+				}))
+			}
+
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 
 			return labcli.WrapStatusError(runDestroyPlayground(cmd.Context(), cli, &opts))
 		},
@@ -39,6 +72,8 @@ func newDestroyCommand(cli labcli.CLI) *cobra.Command {
 
 	flags := cmd.Flags()
 
+	opts.bulk.addFlags(flags, "Destroy")
+
 	flags.BoolVarP(
 		&opts.quiet,
 		"quiet",
@@ -57,6 +92,8 @@ func runDestroyPlayground(ctx context.Context, cli labcli.CLI, opts *destroyOpti
 		return fmt.Errorf("couldn't destroy the playground: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionDestroy, &api.Play{ID: opts.playID})
+
 	s := spinner.New(spinner.CharSets[38], 300*time.Millisecond)
 	s.Writer = cli.AuxStream()
 	s.Prefix = "Waiting for playground to be destroyed... "
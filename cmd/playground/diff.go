@@ -0,0 +1,183 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/labcli"
+	labmanifest "github.com/iximiuz/labctl/internal/manifest"
+)
+
+const diffExample = `  # See what 'labctl playground update my-playground -f manifest.yaml' would change
+  labctl playground diff my-playground -f manifest.yaml
+
+  # The same changes as a JSON Patch (RFC 6902) of the remote manifest
+  labctl playground diff my-playground -f manifest.yaml -o json-patch
+`
+
+type diffOptions struct {
+	name   string
+	file   string
+	output string
+
+	templateOptions
+}
+
+func (opts *diffOptions) validate() error {
+	switch opts.output {
+	case "diff", "json-patch":
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s (supported formats: diff, json-patch)", opts.output)
+	}
+}
+
+func newDiffCommand(cli labcli.CLI) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [flags] <playground-name>",
+		Short: "Show the changes a manifest file would make to an existing playground",
+		Long: `Show the changes a manifest file would make to an existing playground.
+
+Only the parts of the manifest 'labctl playground update' sends are compared. Machines,
+networks, tabs, and other lists of named items are matched by name rather than by position.`,
+		Example:           diffExample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.PlaygroundNames(cli),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return labcli.WrapStatusError(runDiff(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		"manifest.yaml",
+		`Path to the playground manifest YAML file (use - to read it from stdin)`,
+	)
+	flags.StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"diff",
+		`Output format: diff, json-patch`,
+	)
+	opts.addFlags(flags)
+
+	return cmd
+}
+
+func runDiff(ctx context.Context, cli labcli.CLI, opts *diffOptions) error {
+	local, err := readTemplatedManifestFile(opts.file, &opts.templateOptions)
+	if err != nil {
+		return fmt.Errorf("couldn't read manifest: %w", err)
+	}
+
+	if err := applyLegacyAccessMode(local); err != nil {
+		return err
+	}
+
+	remote, err := getManifest(ctx, cli, opts.name)
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "json-patch" {
+		ops, err := labmanifest.JSONPatch(updatableManifest(remote), updatableManifest(local))
+		if err != nil {
+			return fmt.Errorf("couldn't compare the manifests: %w", err)
+		}
+		if ops == nil {
+			ops = []labmanifest.Operation{}
+		}
+
+		enc := json.NewEncoder(cli.OutputStream())
+		enc.SetIndent("", "  ")
+		return enc.Encode(ops)
+	}
+
+	diff, err := labmanifest.UnifiedDiff(updatableManifest(remote), updatableManifest(local), opts.name, manifestFileName(opts.file))
+	if err != nil {
+		return fmt.Errorf("couldn't compare the manifests: %w", err)
+	}
+
+	if diff == "" {
+		cli.PrintAux("No changes.\n")
+		return nil
+	}
+
+	cli.PrintOut("%s", colorizeDiff(diff, cli.OutputStream().IsTerminal()))
+	return nil
+}
+
+// updatableManifest leaves only the parts of the manifest that 'labctl
+// playground update' sends to the server.
+func updatableManifest(m *api.PlaygroundManifest) *api.PlaygroundManifest {
+	return &api.PlaygroundManifest{
+		Title:       m.Title,
+		Description: m.Description,
+		Cover:       m.Cover,
+		Categories:  m.Categories,
+		Markdown:    m.Markdown,
+		Playground: api.PlaygroundSpec{
+			Networks:       m.Playground.Networks,
+			Machines:       m.Playground.Machines,
+			Tabs:           m.Playground.Tabs,
+			InitTasks:      m.Playground.InitTasks,
+			InitConditions: m.Playground.InitConditions,
+			RegistryAuth:   m.Playground.RegistryAuth,
+			AccessControl:  m.Playground.AccessControl,
+		},
+	}
+}
+
+var (
+	diffHeaderStyle  = lipgloss.NewStyle().Bold(true)
+	diffHunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+func colorizeDiff(diff string, color bool) string {
+	if !color {
+		return diff
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		text, newline := strings.CutSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(text, "---"), strings.HasPrefix(text, "+++"):
+			text = diffHeaderStyle.Render(text)
+		case strings.HasPrefix(text, "@@"):
+			text = diffHunkStyle.Render(text)
+		case strings.HasPrefix(text, "+"):
+			text = diffAddedStyle.Render(text)
+		case strings.HasPrefix(text, "-"):
+			text = diffRemovedStyle.Render(text)
+		}
+
+		if newline {
+			text += "\n"
+		}
+		lines[i] = text
+	}
+
+	return strings.Join(lines, "")
+}
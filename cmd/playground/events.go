@@ -0,0 +1,324 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
+)
+
+const (
+	eventKindTask       = "task"
+	eventKindStatus     = "status"
+	eventKindConnection = "connection"
+)
+
+type eventsOptions struct {
+	output  string
+	machine string
+	kinds   []string
+}
+
+func (opts *eventsOptions) validate() error {
+	if opts.output != "timeline" && opts.output != "json" {
+		return fmt.Errorf("invalid output format: %s (supported formats: timeline, json)", opts.output)
+	}
+
+	for _, kind := range opts.kinds {
+		if kind != eventKindTask && kind != eventKindStatus && kind != eventKindConnection {
+			return fmt.Errorf("invalid kind: %s (supported kinds: task, status, connection)", kind)
+		}
+	}
+
+	return nil
+}
+
+func newEventsCommand(cli labcli.CLI) *cobra.Command {
+	var opts eventsOptions
+
+	cmd := &cobra.Command{
+		Use:   "events [flags] <play-id>",
+		Short: "Stream playground events (task updates, machine status changes) until interrupted",
+		Long: `Stream playground events (task updates, machine status changes) until interrupted.
+
+If the connection drops, it's re-established automatically, which shows up as
+connection events (reconnecting, reconnected, resynced). The task and status events
+that catch up on what was missed while disconnected are marked as resync ones.
+
+With --output json, every event is printed as a single-line JSON object (NDJSON),
+which makes the stream easy to consume from scripts:
+
+  labctl playground events --kind task -o json <play-id> | jq -r 'select(.task.status == 40) | .task.name'`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ActivePlays(cli),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runPlaygroundEvents(cmd.Context(), cli, playID, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"timeline",
+		"Output format: timeline, json (one JSON object per line)",
+	)
+
+	flags.StringVar(
+		&opts.machine,
+		"machine",
+		"",
+		"Only show events related to this machine",
+	)
+
+	flags.StringSliceVar(
+		&opts.kinds,
+		"kind",
+		nil,
+		"Only show events of these kinds: task, status, connection (can be repeated or comma-separated)",
+	)
+
+	return cmd
+}
+
+// playEvent is the NDJSON representation of a play connection message. Unlike
+// api.PlayConnMessage, it only carries the fields relevant to its kind and says
+// when the event was received.
+type playEvent struct {
+	At      time.Time       `json:"at"`
+	Kind    string          `json:"kind"`
+	Machine string          `json:"machine,omitempty"`
+	Task    *api.PlayTask   `json:"task,omitempty"`
+	Status  *api.PlayStatus `json:"status,omitempty"`
+
+	// Resync is set for the task and status events made up from a fresh copy
+	// of the play after a reconnect.
+	Resync bool `json:"resync,omitempty"`
+
+	Connection *connectionEvent `json:"connection,omitempty"`
+}
+
+// connectionEvent is the NDJSON representation of api.PlayConnEvent.
+type connectionEvent struct {
+	State    string `json:"state"`
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runPlaygroundEvents(ctx context.Context, cli labcli.CLI, playID string, opts *eventsOptions) error {
+	play, err := cli.Client().GetPlay(ctx, playID)
+	if err != nil {
+		return fmt.Errorf("couldn't get playground: %w", err)
+	}
+
+	if !play.IsActive() {
+		return labcli.NewStatusError(1, "playground %s is not running", playID)
+	}
+
+	if opts.machine != "" && play.GetMachine(opts.machine) == nil {
+		return labcli.NewStatusError(1, "machine %q not found in playground %s", opts.machine, playID)
+	}
+
+	playConn := api.NewPlayConn(ctx, play, cli.Client(), cli.Config().WebSocketOrigin())
+	if err := playConn.Start(); err != nil {
+		return fmt.Errorf("couldn't start play connection: %w", err)
+	}
+	defer playConn.Close()
+
+	cli.PrintAux("Streaming events of playground %s (press Ctrl+C to stop)...\n", playID)
+
+	// Connection events come from a separate channel, so the printing is
+	// serialized.
+	var mu sync.Mutex
+	print := func(ev playEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if opts.output == "json" {
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return fmt.Errorf("couldn't encode event: %w", err)
+			}
+			cli.PrintOut("%s\n", data)
+			return nil
+		}
+
+		cli.PrintOut("%s\n", formatTimelineEvent(ev))
+		return nil
+	}
+
+	eventsDone := make(chan struct{})
+	go func() {
+		defer close(eventsDone)
+
+		for connEv := range playConn.Events() {
+			if ev, ok := toConnectionEvent(connEv, opts, time.Now()); ok {
+				_ = print(ev)
+			}
+		}
+	}()
+
+	err = playConn.Watch(func(msg api.PlayConnMessage) error {
+		if ev, ok := toPlayEvent(msg, opts, time.Now()); ok {
+			return print(ev)
+		}
+		return nil
+	})
+
+	// The final events (e.g., a failed reconnect) are worth seeing.
+	playConn.Close()
+	<-eventsDone
+
+	// The connection ending on its own (e.g., the playground got destroyed)
+	// is the natural end of the stream, not a failure.
+	if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		cli.PrintAux("Play connection closed.\n")
+		return nil
+	}
+	return err
+}
+
+// toPlayEvent applies the --kind and --machine filters. Status events are
+// narrowed down to the machine of interest rather than dropped.
+func toPlayEvent(msg api.PlayConnMessage, opts *eventsOptions, at time.Time) (playEvent, bool) {
+	if len(opts.kinds) > 0 && !slices.Contains(opts.kinds, msg.Kind) {
+		return playEvent{}, false
+	}
+
+	ev := playEvent{
+		At:      at,
+		Kind:    msg.Kind,
+		Machine: msg.Machine,
+		Resync:  msg.Resync,
+	}
+
+	switch msg.Kind {
+	case eventKindTask:
+		if opts.machine != "" && msg.Machine != opts.machine {
+			return playEvent{}, false
+		}
+
+		task := msg.Task
+		ev.Task = &task
+
+	case eventKindStatus:
+		if msg.Status == nil {
+			return playEvent{}, false
+		}
+
+		status := *msg.Status
+		if opts.machine != "" {
+			status.Machines = slices.DeleteFunc(slices.Clone(status.Machines), func(m api.MachineStatus) bool {
+				return m.Name != opts.machine
+			})
+			if len(status.Machines) == 0 {
+				return playEvent{}, false
+			}
+		}
+		ev.Status = &status
+
+	default:
+		if opts.machine != "" && msg.Machine != opts.machine {
+			return playEvent{}, false
+		}
+	}
+
+	return ev, true
+}
+
+// toConnectionEvent applies the --kind filter to a connection event. The
+// --machine filter doesn't apply - the connection is the whole play's.
+func toConnectionEvent(connEv api.PlayConnEvent, opts *eventsOptions, at time.Time) (playEvent, bool) {
+	if len(opts.kinds) > 0 && !slices.Contains(opts.kinds, eventKindConnection) {
+		return playEvent{}, false
+	}
+
+	conn := &connectionEvent{Attempts: connEv.Attempts}
+	switch connEv.Kind {
+	case api.PlayConnDisconnected:
+		conn.State = "reconnecting"
+	case api.PlayConnReconnectFailed:
+		conn.State = "failed"
+	default:
+		conn.State = string(connEv.Kind)
+	}
+	if connEv.Err != nil {
+		conn.Error = connEv.Err.Error()
+	}
+
+	return playEvent{At: at, Kind: eventKindConnection, Connection: conn}, true
+}
+
+func formatTimelineEvent(ev playEvent) string {
+	prefix := fmt.Sprintf("%s  %-7s", ev.At.Format(time.TimeOnly), ev.Kind)
+
+	var resync string
+	if ev.Resync {
+		resync = " (resync)"
+	}
+
+	switch {
+	case ev.Connection != nil:
+		line := fmt.Sprintf("%s  %s", prefix, ev.Connection.State)
+		if ev.Connection.Attempts > 0 {
+			line += fmt.Sprintf(" after %d attempt(s)", ev.Connection.Attempts)
+		}
+		if ev.Connection.Error != "" {
+			line += ": " + ev.Connection.Error
+		}
+		return line
+
+	case ev.Task != nil:
+		line := fmt.Sprintf("%s  %-24s %s", prefix, ev.Task.Name, formatTaskStatus(ev.Task.Status))
+		if ev.Machine != "" {
+			line += " (" + ev.Machine + ")"
+		}
+		return line + resync
+
+	case ev.Status != nil:
+		state := "-"
+		if n := len(ev.Status.StateEvents); n > 0 {
+			state = string(ev.Status.StateEvents[n-1].State)
+		}
+
+		var machines []string
+		for _, m := range ev.Status.Machines {
+			desc := fmt.Sprintf("%s=%s", m.Name, m.State)
+
+			var conds []string
+			for _, c := range m.Conditions {
+				conds = append(conds, c.Name+"="+c.Status)
+			}
+			if len(conds) > 0 {
+				desc += " [" + strings.Join(conds, ", ") + "]"
+			}
+
+			machines = append(machines, desc)
+		}
+
+		return fmt.Sprintf("%s  %-24s %s", prefix, state, strings.Join(machines, "  ")) + resync
+
+	default:
+		return fmt.Sprintf("%s  %s", prefix, ev.Machine)
+	}
+}
@@ -0,0 +1,105 @@
+package playground
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func TestToPlayEventFilters(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	taskMsg := api.PlayConnMessage{
+		Kind:    "task",
+		Machine: "node-01",
+		Task:    api.PlayTask{Name: "init_docker", Init: true, Status: api.PlayTaskStatusCompleted},
+	}
+	statusMsg := api.PlayConnMessage{
+		Kind: "status",
+		Status: &api.PlayStatus{
+			StateEvents: []api.StateEvent{{State: api.StateRunning}},
+			Machines: []api.MachineStatus{
+				{Name: "node-01", State: api.MachineStateRunning, Conditions: []api.Condition{{Name: "Ready", Status: "True"}}},
+				{Name: "node-02", State: api.MachineStateStarting},
+			},
+		},
+	}
+
+	ev, ok := toPlayEvent(taskMsg, &eventsOptions{}, at)
+	require.True(t, ok)
+	assert.Equal(t, "init_docker", ev.Task.Name)
+	assert.Nil(t, ev.Status)
+	assert.Equal(t, "15:04:05  task     init_docker              completed (node-01)", formatTimelineEvent(ev))
+
+	_, ok = toPlayEvent(taskMsg, &eventsOptions{kinds: []string{"status"}}, at)
+	assert.False(t, ok)
+
+	_, ok = toPlayEvent(taskMsg, &eventsOptions{machine: "node-02"}, at)
+	assert.False(t, ok)
+
+	// Status events are narrowed down to the machine, not dropped...
+	ev, ok = toPlayEvent(statusMsg, &eventsOptions{machine: "node-02"}, at)
+	require.True(t, ok)
+	require.Len(t, ev.Status.Machines, 1)
+	assert.Equal(t, "node-02", ev.Status.Machines[0].Name)
+	assert.Equal(t, "15:04:05  status   RUNNING                  node-02=STARTING", formatTimelineEvent(ev))
+
+	// ...without touching the original message.
+	assert.Len(t, statusMsg.Status.Machines, 2)
+
+	ev, ok = toPlayEvent(statusMsg, &eventsOptions{kinds: []string{"status"}}, at)
+	require.True(t, ok)
+	assert.Equal(t, "15:04:05  status   RUNNING                  node-01=RUNNING [Ready=True]  node-02=STARTING", formatTimelineEvent(ev))
+
+	_, ok = toPlayEvent(statusMsg, &eventsOptions{machine: "node-03"}, at)
+	assert.False(t, ok)
+}
+
+func TestEventsOptionsValidate(t *testing.T) {
+	assert.NoError(t, (&eventsOptions{output: "json", kinds: []string{"task", "status"}}).validate())
+	assert.Error(t, (&eventsOptions{output: "yaml"}).validate())
+	assert.Error(t, (&eventsOptions{output: "timeline", kinds: []string{"journal"}}).validate())
+}
+
+func TestToConnectionEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	ev, ok := toConnectionEvent(api.PlayConnEvent{Kind: api.PlayConnDisconnected, Err: errors.New("EOF")}, &eventsOptions{}, at)
+	require.True(t, ok)
+	assert.Equal(t, "reconnecting", ev.Connection.State)
+	assert.Equal(t, "15:04:05  connection  reconnecting: EOF", formatTimelineEvent(ev))
+
+	ev, ok = toConnectionEvent(api.PlayConnEvent{Kind: api.PlayConnReconnected, Attempts: 2}, &eventsOptions{machine: "node-01"}, at)
+	require.True(t, ok)
+	assert.Equal(t, "15:04:05  connection  reconnected after 2 attempt(s)", formatTimelineEvent(ev))
+
+	ev, ok = toConnectionEvent(api.PlayConnEvent{Kind: api.PlayConnResynced}, &eventsOptions{kinds: []string{"connection"}}, at)
+	require.True(t, ok)
+	assert.Equal(t, "resynced", ev.Connection.State)
+
+	_, ok = toConnectionEvent(api.PlayConnEvent{Kind: api.PlayConnResynced}, &eventsOptions{kinds: []string{"task"}}, at)
+	assert.False(t, ok)
+}
+
+func TestResyncEventsAreMarked(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	ev, ok := toPlayEvent(api.PlayConnMessage{
+		Kind:   "task",
+		Task:   api.PlayTask{Name: "init_docker", Status: api.PlayTaskStatusCompleted},
+		Resync: true,
+	}, &eventsOptions{}, at)
+	require.True(t, ok)
+	assert.True(t, ev.Resync)
+	assert.Equal(t, "15:04:05  task     init_docker              completed (resync)", formatTimelineEvent(ev))
+
+	data, err := json.Marshal(ev)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"resync":true`)
+}
@@ -0,0 +1,106 @@
+package playground
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/taskgraph"
+)
+
+const graphExample = `  # Show the init task graph of a manifest as text
+  labctl playground graph -f manifest.yaml
+
+  # Render it with Graphviz
+  labctl playground graph -f manifest.yaml -o dot | dot -Tsvg > tasks.svg
+
+  # ...or as a Mermaid flowchart (e.g., for a README)
+  labctl playground graph -f manifest.yaml -o mermaid
+
+  # The statuses and durations of the tasks of a running playground
+  labctl playground tasks <play-id> --graph
+`
+
+type graphOptions struct {
+	file   string
+	output string
+
+	templateOptions
+}
+
+func (opts *graphOptions) validate() error {
+	return validateGraphFormat(opts.output)
+}
+
+func newGraphCommand(cli labcli.CLI) *cobra.Command {
+	var opts graphOptions
+
+	cmd := &cobra.Command{
+		Use:     "graph [flags]",
+		Short:   "Show the dependency graph of the init tasks in a playground manifest",
+		Example: graphExample,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runGraph(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		"manifest.yaml",
+		`Path to the playground manifest YAML file (use - to read it from stdin)`,
+	)
+	flags.StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"ascii",
+		`Output format: ascii, dot, mermaid`,
+	)
+	opts.addFlags(flags)
+
+	return cmd
+}
+
+func runGraph(cli labcli.CLI, opts *graphOptions) error {
+	manifest, err := readValidManifestFile(opts.file, &opts.templateOptions)
+	if err != nil {
+		return err
+	}
+
+	graph, err := taskgraph.FromManifest(manifest.Playground.InitTasks)
+	if err != nil {
+		return err
+	}
+
+	cli.PrintOut("%s", renderGraph(graph, opts.output, cli.OutputStream().IsTerminal()))
+	return nil
+}
+
+func validateGraphFormat(format string) error {
+	switch format {
+	case "ascii", "dot", "mermaid":
+		return nil
+	default:
+		return fmt.Errorf("invalid graph format: %s (supported formats: ascii, dot, mermaid)", format)
+	}
+}
+
+func renderGraph(graph *taskgraph.Graph, format string, color bool) string {
+	switch format {
+	case "dot":
+		return graph.DOT()
+	case "mermaid":
+		return graph.Mermaid()
+	default:
+		return graph.ASCII(color)
+	}
+}
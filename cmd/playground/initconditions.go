@@ -0,0 +1,176 @@
+package playground
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+// resolveInitConditions checks the --init-condition values against the ones
+// the playground declares. If some required values are missing, they are
+// asked for in a form (in a terminal) or listed in the returned error.
+func resolveInitConditions(
+	cli labcli.CLI,
+	declared []api.InitConditionValue,
+	given map[string]string,
+) (map[string]string, error) {
+	if len(declared) == 0 {
+		return given, nil
+	}
+
+	values := map[string]string{}
+	for key, value := range given {
+		idx := slices.IndexFunc(declared, func(v api.InitConditionValue) bool { return v.Key == key })
+		if idx == -1 {
+			return nil, labcli.NewStatusError(1,
+				"unknown init condition %q\n\nThe playground's init conditions:\n%s",
+				key, formatInitConditions(declared))
+		}
+
+		if err := validateInitCondition(declared[idx], value); err != nil {
+			return nil, labcli.NewStatusError(1, "invalid init condition %s: %s", key, err)
+		}
+
+		values[key] = value
+	}
+
+	var missing []api.InitConditionValue
+	for _, v := range declared {
+		if _, ok := values[v.Key]; !ok && isInitConditionRequired(v) {
+			missing = append(missing, v)
+		}
+	}
+
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	if !cli.InputStream().IsTerminal() {
+		return nil, labcli.NewStatusError(1,
+			"missing required init conditions (set them with --init-condition key=value):\n%s",
+			formatInitConditions(missing))
+	}
+
+	if err := promptInitConditions(declared, values); err != nil {
+		return nil, fmt.Errorf("couldn't read the init conditions: %w", err)
+	}
+
+	return values, nil
+}
+
+// promptInitConditions renders a form with all the declared values that
+// weren't given on the command line, prefilled with their defaults.
+func promptInitConditions(declared []api.InitConditionValue, values map[string]string) error {
+	var (
+		fields  []huh.Field
+		answers = map[string]*string{}
+	)
+
+	for _, v := range declared {
+		if _, ok := values[v.Key]; ok {
+			continue
+		}
+
+		answer := v.Default
+		answers[v.Key] = &answer
+
+		if len(v.Options) > 0 {
+			options := huh.NewOptions(v.Options...)
+			if v.Nullable {
+				options = append([]huh.Option[string]{huh.NewOption("(none)", "")}, options...)
+			}
+
+			fields = append(fields, huh.NewSelect[string]().
+				Title(v.Key).
+				Options(options...).
+				Value(&answer))
+			continue
+		}
+
+		fields = append(fields, huh.NewInput().
+			Title(v.Key).
+			Placeholder(v.Placeholder).
+			Validate(func(s string) error { return validateInitCondition(v, s) }).
+			Value(&answer))
+	}
+
+	if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
+		return err
+	}
+
+	for key, answer := range answers {
+		if *answer != "" {
+			values[key] = *answer
+		}
+	}
+	return nil
+}
+
+func isInitConditionRequired(v api.InitConditionValue) bool {
+	return !v.Nullable && v.Default == ""
+}
+
+func validateInitCondition(v api.InitConditionValue, value string) error {
+	if value == "" {
+		if isInitConditionRequired(v) {
+			return fmt.Errorf("value is required")
+		}
+		return nil
+	}
+
+	if len(v.Options) > 0 && !slices.Contains(v.Options, value) {
+		return fmt.Errorf("%q is not one of: %s", value, strings.Join(v.Options, ", "))
+	}
+
+	if v.ValidationRegex != "" {
+		re, err := regexp.Compile(v.ValidationRegex)
+		if err != nil {
+			// Leave it to the server - the pattern may use a syntax Go doesn't support.
+			slog.Debug("Skipping init condition validation", "key", v.Key, "regex", v.ValidationRegex, "error", err.Error())
+			return nil
+		}
+
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q doesn't match %s", value, v.ValidationRegex)
+		}
+	}
+
+	return nil
+}
+
+func formatInitConditions(values []api.InitConditionValue) string {
+	var lines []string
+	for _, v := range values {
+		var details []string
+		if isInitConditionRequired(v) {
+			details = append(details, "required")
+		}
+		if len(v.Options) > 0 {
+			details = append(details, "one of: "+strings.Join(v.Options, ", "))
+		}
+		if v.ValidationRegex != "" {
+			details = append(details, "matching: "+v.ValidationRegex)
+		}
+		if v.Default != "" {
+			details = append(details, "default: "+v.Default)
+		}
+		if v.Placeholder != "" {
+			details = append(details, "e.g., "+v.Placeholder)
+		}
+
+		line := "  - " + v.Key
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, "; ") + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,50 @@
+package playground
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+func TestResolveInitConditions(t *testing.T) {
+	cli := labcli.NewCLI(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard, "test")
+
+	declared := []api.InitConditionValue{
+		{Key: "version", Options: []string{"1.30", "1.31"}, Default: "1.31"},
+		{Key: "nodes", ValidationRegex: `^[1-5]$`},
+		{Key: "registry", Nullable: true},
+	}
+
+	values, err := resolveInitConditions(cli, declared, map[string]string{"nodes": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"nodes": "3"}, values)
+
+	// Nothing declared - nothing to check.
+	values, err = resolveInitConditions(cli, nil, map[string]string{"anything": "goes"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"anything": "goes"}, values)
+
+	for msg, given := range map[string]map[string]string{
+		"Unknown init condition":           {"nodes": "3", "color": "blue"},
+		`"1.29" is not one of: 1.30, 1.31`: {"nodes": "3", "version": "1.29"},
+		`"9" doesn't match ^[1-5]$`:        {"nodes": "9"},
+		"Missing required init conditions": {"version": "1.30"},
+	} {
+		_, err := resolveInitConditions(cli, declared, given)
+		if assert.Error(t, err, msg) {
+			assert.Contains(t, err.Error(), msg)
+		}
+	}
+
+	// Non-interactive runs get the list of what's required.
+	_, err = resolveInitConditions(cli, declared, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "  - nodes (required; matching: ^[1-5]$)")
+	assert.NotContains(t, err.Error(), "registry")
+}
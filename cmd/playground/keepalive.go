@@ -0,0 +1,92 @@
+package playground
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/keepalive"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
+)
+
+const keepaliveExample = `  # Warn 15, 5, and 1 minute(s) before the playground expires
+  labctl playground keepalive 65e78a64366c2b0cf9ddc34c
+
+  # Also keep extending the playground by 30 minutes, up to 6 hours in total
+  labctl playground keepalive 65e78a64366c2b0cf9ddc34c --extend 30m --max-lifetime 6h
+`
+
+type keepaliveOptions struct {
+	playID string
+
+	warnAt      []time.Duration
+	extendBy    time.Duration
+	maxLifetime time.Duration
+	interval    time.Duration
+}
+
+func newKeepaliveCommand(cli labcli.CLI) *cobra.Command {
+	var opts keepaliveOptions
+
+	cmd := &cobra.Command{
+		Use:               "keepalive [flags] <playground-id>",
+		Short:             `Watch a playground's remaining time, warning before it expires and optionally extending its lifetime`,
+		Example:           keepaliveExample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ActivePlays(cli),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
+
+			return labcli.WrapStatusError(runKeepalive(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.DurationSliceVar(
+		&opts.warnAt,
+		"warn",
+		keepalive.DefaultWarnAt,
+		`Remaining times to print a warning at`,
+	)
+	flags.DurationVar(
+		&opts.extendBy,
+		"extend",
+		0,
+		`Extend the playground's lifetime by this much when it's about to expire (default: don't extend)`,
+	)
+	flags.DurationVar(
+		&opts.maxLifetime,
+		"max-lifetime",
+		0,
+		`Don't extend the playground's lifetime beyond this (the account's limit applies regardless)`,
+	)
+	flags.DurationVar(
+		&opts.interval,
+		"interval",
+		keepalive.DefaultInterval,
+		`How often to check the remaining time`,
+	)
+
+	return cmd
+}
+
+func runKeepalive(ctx context.Context, cli labcli.CLI, opts *keepaliveOptions) error {
+	cli.PrintAux("Watching playground %s (press Ctrl+C to stop)...\n", opts.playID)
+
+	return keepalive.New(cli.Client(), keepalive.Options{
+		PlayID:      opts.playID,
+		WarnAt:      opts.warnAt,
+		ExtendBy:    opts.extendBy,
+		MaxLifetime: opts.maxLifetime,
+		Interval:    opts.interval,
+		Out:         cli,
+	}).Run(ctx)
+}
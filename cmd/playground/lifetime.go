@@ -7,33 +7,73 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
+const lifetimeExample = `  # Show the lifetime of a playground
+  labctl playground lifetime 65e78a64366c2b0cf9ddc34c
+
+  # Set the lifetime of a playground to 3 hours
+  labctl playground lifetime 65e78a64366c2b0cf9ddc34c 3h
+
+  # Set the lifetime of all running k3s playgrounds to 4 hours
+  labctl playground lifetime --all --filter playground=k3s 4h
+`
+
 type lifetimeOptions struct {
 	playID   string
 	lifetime time.Duration
+
+	bulk bulkOptions
 }
 
 func newLifetimeCommand(cli labcli.CLI) *cobra.Command {
 	var opts lifetimeOptions
 
 	cmd := &cobra.Command{
-		Use:               "lifetime <playground-id> [new-lifetime]",
+		Use:               "lifetime (<playground-id> [new-lifetime] | --all [flags] <new-lifetime>)",
 		Short:             `Show or set a playground's lifetime - the total session duration counted from the playground's start (e.g., 90m, 3h, 2h30m)`,
-		Args:              cobra.RangeArgs(1, 2),
+		Example:           lifetimeExample,
+		Args:              opts.bulk.args(cobra.RangeArgs(1, 2), cobra.ExactArgs(1)),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			if err := opts.bulk.validate(cmd); err != nil {
+				return err
+			}
+
+			if opts.bulk.all {
+				lifetime, err := parseLifetime(args[0])
+				if err != nil {
+					return labcli.WrapStatusError(err)
+				}
+
+				return labcli.WrapStatusError(runBulk(cmd.Context(), cli, &opts.bulk, bulkAction{
+					verb:     "update the lifetime of",
+					past:     "updated",
+					eligible: (*api.Play).IsActive,
+					run: func(ctx context.Context, play *api.Play) error {
+						_, err := cli.Client().SetPlayMaxPlayTime(ctx, play.ID, int(lifetime.Minutes()))
+						return err
+					},
+				}))
+			}
+
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 
 			if len(args) == 1 {
 				return labcli.WrapStatusError(runShowPlaygroundLifetime(cmd.Context(), cli, &opts))
 			}
 
-			lifetime, err := time.ParseDuration(args[1])
+			lifetime, err := parseLifetime(args[1])
 			if err != nil {
-				return labcli.WrapStatusError(fmt.Errorf("invalid lifetime %q: %w", args[1], err))
+				return labcli.WrapStatusError(err)
 			}
 			opts.lifetime = lifetime
 
@@ -41,9 +81,22 @@ func newLifetimeCommand(cli labcli.CLI) *cobra.Command {
 		},
 	}
 
+	opts.bulk.addFlags(cmd.Flags(), "Set the lifetime of")
+
 	return cmd
 }
 
+func parseLifetime(s string) (time.Duration, error) {
+	lifetime, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lifetime %q: %w", s, err)
+	}
+	if lifetime < time.Minute {
+		return 0, fmt.Errorf("lifetime must be at least 1 minute")
+	}
+	return lifetime, nil
+}
+
 func runShowPlaygroundLifetime(ctx context.Context, cli labcli.CLI, opts *lifetimeOptions) error {
 	play, err := cli.Client().GetPlay(ctx, opts.playID)
 	if err != nil {
@@ -71,9 +124,6 @@ func runShowPlaygroundLifetime(ctx context.Context, cli labcli.CLI, opts *lifeti
 
 func runSetPlaygroundLifetime(ctx context.Context, cli labcli.CLI, opts *lifetimeOptions) error {
 	minutes := int(opts.lifetime.Minutes())
-	if minutes < 1 {
-		return fmt.Errorf("lifetime must be at least 1 minute")
-	}
 
 	cli.PrintAux("Setting lifetime of playground %s to %s...\n", opts.playID, opts.lifetime)
 
@@ -0,0 +1,43 @@
+package playground
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+func TestLifetimeArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		flags   []string
+		args    []string
+		wantErr bool
+	}{
+		{name: "show", args: []string{"play-1"}},
+		{name: "set", args: []string{"play-1", "3h"}},
+		{name: "no playground", wantErr: true},
+		{name: "too many", args: []string{"play-1", "3h", "4h"}, wantErr: true},
+		{name: "all", flags: []string{"--all"}, args: []string{"4h"}},
+		{name: "all without lifetime", flags: []string{"--all"}, wantErr: true},
+		{name: "all with playground", flags: []string{"--all"}, args: []string{"play-1", "4h"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := labcli.NewCLI(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard, "test")
+
+			cmd := newLifetimeCommand(cli)
+			require.NoError(t, cmd.ParseFlags(tc.flags))
+
+			err := cmd.Args(cmd, tc.args)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
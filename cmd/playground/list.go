@@ -13,6 +13,7 @@ import (
 
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type listOptions struct {
@@ -20,6 +21,9 @@ type listOptions struct {
 	quiet  bool
 	filter string
 	output string
+
+	states    []string
+	olderThan time.Duration
 }
 
 func (opts *listOptions) validate() error {
@@ -67,6 +71,18 @@ func newListCommand(cli labcli.CLI) *cobra.Command {
 		"",
 		`Filter playgrounds by tutorial=<name>, challenge=<name>, course=<name>, or playground=<name>`,
 	)
+	flags.StringSliceVar(
+		&opts.states,
+		"state",
+		nil,
+		fmt.Sprintf(`Only list playgrounds in the given state(s) (%s)`, joinPlayStates()),
+	)
+	flags.DurationVar(
+		&opts.olderThan,
+		"older-than",
+		0,
+		`Only list playgrounds created longer ago than the given duration (e.g., 2h, 30m)`,
+	)
 	flags.StringVarP(
 		&opts.output,
 		"output",
@@ -84,31 +100,21 @@ func runListPlays(ctx context.Context, cli labcli.CLI, opts *listOptions) error
 		return err
 	}
 
-	recentPlays, err := cli.Client().ListPlays(ctx, api.ListPlaysQueryParams{})
+	filter, err = filter.withSelectors(opts.states, opts.olderThan)
 	if err != nil {
-		return fmt.Errorf("couldn't list playgrounds: %w", err)
+		return err
 	}
 
-	persistentPlays, err := cli.Client().ListPlays(ctx, api.ListPlaysQueryParams{Persistent: true})
+	plays, err := playref.List(ctx, cli.Client())
 	if err != nil {
-		return fmt.Errorf("couldn't list stopped playgrounds: %w", err)
-	}
-
-	plays := append([]*api.Play{}, recentPlays...)
-	for _, play := range persistentPlays {
-		if !slices.ContainsFunc(plays, func(p *api.Play) bool {
-			return p.ID == play.ID
-		}) {
-			plays = append(plays, play)
-		}
+		return err
 	}
-	slices.SortFunc(plays, func(a, b *api.Play) int {
-		return strings.Compare(b.UpdatedAt, a.UpdatedAt)
-	})
 
 	var filteredPlays []*api.Play
 	for _, play := range plays {
-		if (opts.all || play.IsActive() || play.StateIs(api.StateStopped)) && filter.matches(play) {
+		// An explicit --state overrides the default (active or stopped) selection.
+		visible := opts.all || filter.hasStates() || play.IsActive() || play.StateIs(api.StateStopped)
+		if visible && filter.matches(play) {
 			filteredPlays = append(filteredPlays, play)
 		}
 	}
@@ -218,14 +224,56 @@ func parseFilter(filter string) (*playFilter, error) {
 type playFilter struct {
 	kind  string
 	value string
+
+	// states, if not empty, limits the matches to plays in these states.
+	states []api.PlayState
+
+	// olderThan, if set, limits the matches to plays created earlier than
+	// that long ago.
+	olderThan time.Duration
+}
+
+// withSelectors adds the --state and --older-than predicates to the filter.
+func (f *playFilter) withSelectors(states []string, olderThan time.Duration) (*playFilter, error) {
+	if olderThan < 0 {
+		return nil, fmt.Errorf("invalid --older-than value: %s (must be positive)", olderThan)
+	}
+	f.olderThan = olderThan
+
+	for _, s := range states {
+		state := api.PlayState(strings.ToUpper(s))
+		if !slices.Contains(playStates, state) {
+			return nil, fmt.Errorf("unknown playground state: %s (supported states: %s)", s, joinPlayStates())
+		}
+		f.states = append(f.states, state)
+	}
+
+	return f, nil
+}
+
+func (f *playFilter) hasStates() bool {
+	return f != nil && len(f.states) > 0
 }
 
 func (f *playFilter) matches(play *api.Play) bool {
-	if f == nil || f.kind == "" {
+	if f == nil {
 		return true
 	}
 
+	if len(f.states) > 0 && !slices.Contains(f.states, play.State()) {
+		return false
+	}
+
+	if f.olderThan > 0 {
+		createdAt := safeParseTime(play.CreatedAt)
+		if createdAt.IsZero() || time.Since(createdAt) < f.olderThan {
+			return false
+		}
+	}
+
 	switch f.kind {
+	case "":
+		return true
 	case "tutorial":
 		return play.TutorialName == f.value
 	case "challenge":
@@ -238,3 +286,22 @@ func (f *playFilter) matches(play *api.Play) bool {
 		return false
 	}
 }
+
+var playStates = []api.PlayState{
+	api.StateCreated,
+	api.StateStarting,
+	api.StateRunning,
+	api.StateStopping,
+	api.StateStopped,
+	api.StateDestroying,
+	api.StateDestroyed,
+	api.StateFailed,
+}
+
+func joinPlayStates() string {
+	var states []string
+	for _, s := range playStates {
+		states = append(states, string(s))
+	}
+	return strings.Join(states, ", ")
+}
@@ -0,0 +1,62 @@
+package playground
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func TestPlayFilterSelectors(t *testing.T) {
+	newPlay := func(playground string, state api.PlayState, age time.Duration) *api.Play {
+		p := &api.Play{
+			CreatedAt: time.Now().Add(-age).Format(time.RFC3339),
+			Status:    &api.PlayStatus{StateEvents: []api.StateEvent{{State: state}}},
+		}
+		p.Playground.Name = playground
+		return p
+	}
+
+	oldStoppedK3s := newPlay("k3s", api.StateStopped, 3*time.Hour)
+	newStoppedK3s := newPlay("k3s", api.StateStopped, time.Hour)
+	oldRunningK3s := newPlay("k3s", api.StateRunning, 3*time.Hour)
+	oldStoppedDocker := newPlay("docker", api.StateStopped, 3*time.Hour)
+
+	filter, err := parseFilter("playground=k3s")
+	require.NoError(t, err)
+
+	filter, err = filter.withSelectors([]string{"stopped"}, 2*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, filter.hasStates())
+
+	assert.True(t, filter.matches(oldStoppedK3s))
+	assert.False(t, filter.matches(newStoppedK3s))
+	assert.False(t, filter.matches(oldRunningK3s))
+	assert.False(t, filter.matches(oldStoppedDocker))
+
+	// No selectors match everything.
+	filter, err = parseFilter("")
+	require.NoError(t, err)
+	filter, err = filter.withSelectors(nil, 0)
+	require.NoError(t, err)
+	assert.False(t, filter.hasStates())
+
+	for _, p := range []*api.Play{oldStoppedK3s, newStoppedK3s, oldRunningK3s, oldStoppedDocker} {
+		assert.True(t, filter.matches(p))
+	}
+
+	// Several states.
+	filter, err = (&playFilter{}).withSelectors([]string{"STOPPED", "running"}, 0)
+	require.NoError(t, err)
+	assert.True(t, filter.matches(oldRunningK3s))
+	assert.True(t, filter.matches(oldStoppedDocker))
+
+	_, err = (&playFilter{}).withSelectors([]string{"SLEEPING"}, 0)
+	assert.Error(t, err)
+
+	_, err = (&playFilter{}).withSelectors(nil, -time.Hour)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 func newMachineCommand(cli labcli.CLI) *cobra.Command {
@@ -43,7 +44,11 @@ func newMachineRebootCommand(cli labcli.CLI) *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runRebootMachine(cmd.Context(), cli, args[0], args[1]))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runRebootMachine(cmd.Context(), cli, playID, args[1]))
 		},
 	}
 }
@@ -74,7 +79,11 @@ func newMachineStopCommand(cli labcli.CLI) *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runStopMachine(cmd.Context(), cli, args[0], args[1]))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runStopMachine(cmd.Context(), cli, playID, args[1]))
 		},
 	}
 }
@@ -105,7 +114,11 @@ func newMachineRestartCommand(cli labcli.CLI) *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runRestartMachine(cmd.Context(), cli, args[0], args[1]))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runRestartMachine(cmd.Context(), cli, playID, args[1]))
 		},
 	}
 }
@@ -136,7 +149,11 @@ func newMachineConsoleCommand(cli labcli.CLI) *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runMachineConsole(cmd.Context(), cli, args[0], args[1]))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runMachineConsole(cmd.Context(), cli, playID, args[1]))
 		},
 	}
 }
@@ -191,7 +208,11 @@ func newMachineJournalCommand(cli labcli.CLI) *cobra.Command {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runMachineJournal(cmd.Context(), cli, args[0], args[1], &opts))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runMachineJournal(cmd.Context(), cli, playID, args[1], &opts))
 		},
 	}
 
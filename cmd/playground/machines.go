@@ -9,6 +9,7 @@ import (
 
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 func newMachinesCommand(cli labcli.CLI) *cobra.Command {
@@ -18,8 +19,11 @@ func newMachinesCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.NonDestroyedPlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			playgroundID := args[0]
-			return labcli.WrapStatusError(runListMachines(cmd.Context(), cli, playgroundID))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runListMachines(cmd.Context(), cli, playID))
 		},
 	}
 
@@ -3,31 +3,77 @@ package playground
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/iximiuz/labctl/api"
+	clicontent "github.com/iximiuz/labctl/cmd/content"
 	"github.com/iximiuz/labctl/internal/labcli"
+	labmanifest "github.com/iximiuz/labctl/internal/manifest"
 )
 
+const manifestExample = `  # Print the manifest of a playground
+  labctl playground manifest my-playground
+
+  # Save the manifest to ./my-playground/manifest.yaml with the startup files
+  # and init task scripts as separate files (referenced via contentFrom and runFrom)
+  labctl playground manifest my-playground --split
+
+  # ...and update the playground from the edited files later
+  labctl playground update my-playground -f my-playground/manifest.yaml
+`
+
 type manifestOptions struct {
 	name string
+
+	split bool
+	clicontent.DirOptions
+	force bool
 }
 
 func newManifestCommand(cli labcli.CLI) *cobra.Command {
 	var opts manifestOptions
 
 	cmd := &cobra.Command{
-		Use:   "manifest <playground-name>",
-		Short: "View playground manifest",
-		Args:  cobra.ExactArgs(1),
+		Use:     "manifest [flags] <playground-name>",
+		Short:   "View playground manifest",
+		Example: manifestExample,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.split && (opts.DirOptions.IsSet() || opts.force) {
+				return labcli.NewStatusError(1, "--dir and --force can only be used with --split")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.name = args[0]
+			if opts.split {
+				return labcli.WrapStatusError(runSplitManifest(cmd.Context(), cli, &opts))
+			}
 			return labcli.WrapStatusError(runManifest(cmd.Context(), cli, &opts))
 		},
 	}
 
+	flags := cmd.Flags()
+
+	flags.BoolVar(
+		&opts.split,
+		"split",
+		false,
+		`Save the manifest to a directory, moving the startup files and init task scripts to separate files`,
+	)
+	opts.AddDirFlag(flags, "Local directory to save the split manifest to (default: $CWD/<playground-name>)")
+	flags.BoolVar(
+		&opts.force,
+		"force",
+		false,
+		`Overwrite existing local files without confirmation`,
+	)
+
 	return cmd
 }
 
@@ -46,6 +92,58 @@ func runManifest(ctx context.Context, cli labcli.CLI, opts *manifestOptions) err
 	return nil
 }
 
+func runSplitManifest(ctx context.Context, cli labcli.CLI, opts *manifestOptions) error {
+	manifest, err := getManifest(ctx, cli, opts.name)
+	if err != nil {
+		return err
+	}
+
+	dir, err := opts.ContentDir(opts.name)
+	if err != nil {
+		return err
+	}
+
+	files := labmanifest.SplitFiles(manifest)
+
+	bytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal manifest: %w", err)
+	}
+	files["manifest.yaml"] = string(bytes)
+
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	cli.PrintAux("Saving the manifest to %s...\n", dir)
+
+	for _, path := range paths {
+		dest := filepath.Join(dir, filepath.FromSlash(path))
+		if _, err := os.Stat(dest); err == nil {
+			cli.PrintAux("File %s already exists.\n", dest)
+			if !opts.force && !cli.Confirm("Overwrite?", "Yes", "No") {
+				cli.PrintAux("Skipping...\n")
+				continue
+			}
+
+			cli.PrintAux("Overwriting...\n")
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("couldn't create directory %s: %w", filepath.Dir(dest), err)
+		}
+
+		if err := os.WriteFile(dest, []byte(files[path]), 0644); err != nil {
+			return fmt.Errorf("couldn't write %s: %w", dest, err)
+		}
+	}
+
+	cli.PrintOut("%s\n", filepath.Join(dir, "manifest.yaml"))
+	return nil
+}
+
 func getManifest(ctx context.Context, cli labcli.CLI, name string) (*api.PlaygroundManifest, error) {
 	playground, err := cli.Client().GetPlayground(ctx, name, &api.GetPlaygroundOptions{
 		Format: "extended",
@@ -7,14 +7,18 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 const persistCommandTimeout = 5 * time.Minute
 
 type persistOptions struct {
 	playID string
+
+	bulk bulkOptions
 }
 
 func newPersistCommand(cli labcli.CLI) *cobra.Command {
@@ -23,15 +27,37 @@ func newPersistCommand(cli labcli.CLI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "persist [flags] <playground-id>",
 		Short:             `Makes an active playground session persistent`,
-		Args:              cobra.ExactArgs(1),
+		Example:           `  labctl playground persist --all --filter playground=k3s`,
+		Args:              opts.bulk.args(cobra.ExactArgs(1), cobra.ExactArgs(0)),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			if err := opts.bulk.validate(cmd); err != nil {
+				return err
+			}
+
+			if opts.bulk.all {
+				return labcli.WrapStatusError(runBulk(cmd.Context(), cli, &opts.bulk, bulkAction{
+					verb:     "persist",
+					past:     "persisted",
+					eligible: (*api.Play).IsActive,
+					run: func(ctx context.Context, play *api.Play) error {
+						return cli.Client().PersistPlay(ctx, play.ID)
+					},
+				}))
+			}
+
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 
 			return labcli.WrapStatusError(runPersistPlayground(cmd.Context(), cli, &opts))
 		},
 	}
 
+	opts.bulk.addFlags(cmd.Flags(), "Persist")
+
 	return cmd
 }
 
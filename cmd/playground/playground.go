@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/labcli"
+	labmanifest "github.com/iximiuz/labctl/internal/manifest"
 )
 
 func NewCommand(cli labcli.CLI) *cobra.Command {
@@ -28,35 +31,41 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 		newDestroyCommand(cli),
 		newPersistCommand(cli),
 		newLifetimeCommand(cli),
+		newKeepaliveCommand(cli),
 		newRegionCommand(cli),
 		newMachinesCommand(cli),
 		newMachineCommand(cli),
 		newCreateCommand(cli),
 		newManifestCommand(cli),
 		newUpdateCommand(cli),
+		newDiffCommand(cli),
+		newRenderCommand(cli),
+		newValidateCommand(cli),
+		newSchemaCommand(cli),
 		newRemoveCommand(cli),
 		newTasksCommand(cli),
+		newGraphCommand(cli),
 		newWaitCommand(cli),
+		newEventsCommand(cli),
 		newStatusCommand(cli),
+		newAliasCommand(cli),
+		newUnaliasCommand(cli),
 	)
 
 	return cmd
 }
 
 func readManifestFile(filePath string) (*api.PlaygroundManifest, error) {
-	var rawManifest []byte
-	var err error
+	return readTemplatedManifestFile(filePath, nil)
+}
 
-	if filePath == "-" {
-		rawManifest, err = io.ReadAll(os.Stdin)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read manifest from stdin: %w", err)
-		}
-	} else {
-		rawManifest, err = os.ReadFile(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read manifest file: %w", err)
-		}
+// readTemplatedManifestFile is readManifestFile that renders the manifest
+// template and applies the overlays first. The contentFrom and runFrom files
+// are inlined either way.
+func readTemplatedManifestFile(filePath string, tmpl *templateOptions) (*api.PlaygroundManifest, error) {
+	rawManifest, err := tmpl.render(filePath)
+	if err != nil {
+		return nil, err
 	}
 
 	var manifest api.PlaygroundManifest
@@ -68,5 +77,79 @@ func readManifestFile(filePath string) (*api.PlaygroundManifest, error) {
 		return nil, fmt.Errorf("invalid manifest kind: %s (expected 'playground')", manifest.Kind)
 	}
 
+	if err := labmanifest.InlineFiles(&manifest, manifestDir(filePath)); err != nil {
+		return nil, err
+	}
+
 	return &manifest, nil
 }
+
+// readValidManifestFile is readTemplatedManifestFile that also runs the client-side
+// checks of the manifest and fails with all the problems found.
+func readValidManifestFile(filePath string, tmpl *templateOptions) (*api.PlaygroundManifest, error) {
+	rawManifest, err := tmpl.render(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, findings, err := labmanifest.Validate(rawManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(findings) > 0 && tmpl.isSet() {
+		// The line numbers are of the rendered manifest, not of the file.
+		return nil, labcli.NewStatusError(1, "the rendered manifest of %s has %d problem(s) (see 'labctl playground render' for the line numbers):\n%s",
+			manifestFileName(filePath), len(findings), formatFindings(findings))
+	}
+	if len(findings) > 0 {
+		return nil, labcli.NewStatusError(1, "%s has %d problem(s):\n%s",
+			manifestFileName(filePath), len(findings), formatFindings(findings))
+	}
+
+	if err := labmanifest.InlineFiles(manifest, manifestDir(filePath)); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func readManifestData(filePath string) ([]byte, error) {
+	if filePath == "-" {
+		rawManifest, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest from stdin: %w", err)
+		}
+		return rawManifest, nil
+	}
+
+	rawManifest, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file: %w", err)
+	}
+	return rawManifest, nil
+}
+
+func manifestFileName(filePath string) string {
+	if filePath == "-" {
+		return "<stdin>"
+	}
+	return filePath
+}
+
+// manifestDir is the directory the contentFrom and runFrom paths of the
+// manifest are relative to.
+func manifestDir(filePath string) string {
+	if filePath == "-" {
+		return "."
+	}
+	return filepath.Dir(filePath)
+}
+
+func formatFindings(findings []labmanifest.Finding) string {
+	var lines []string
+	for _, f := range findings {
+		lines = append(lines, "  - "+f.String())
+	}
+	return strings.Join(lines, "\n")
+}
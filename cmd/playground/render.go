@@ -0,0 +1,172 @@
+package playground
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+	labmanifest "github.com/iximiuz/labctl/internal/manifest"
+)
+
+const renderExample = `  # A manifest template, e.g.:
+  #
+  #   machines:
+  #   {{- range $i := seq .Values.nodes }}
+  #     - name: node-{{ printf "%02d" $i }}
+  #       resources:
+  #         ramSize: {{ $.Values.ramSize | default "2Gi" }}
+  #   {{- end }}
+  labctl playground render -f manifest.yaml --set nodes=3 --set ramSize=4Gi
+
+  # The values from files (the later ones take priority, and --set over all of them)
+  labctl playground render -f manifest.yaml --values common.yaml --values prod.yaml
+
+  # An overlay patching the base manifest's machines, tabs, and init tasks by name
+  # (an item with "$patch: delete" removes the matching one)
+  labctl playground render -f manifest.yaml --overlay overlays/prod.yaml
+
+  # The same flags work with 'labctl playground create/update/start -f'
+  labctl playground update my-playground -f manifest.yaml --values prod.yaml --diff
+`
+
+// templateOptions are the flags turning a manifest file into the manifest
+// to send: the template values and the overlays.
+type templateOptions struct {
+	set      []string
+	values   []string
+	overlays []string
+}
+
+func (o *templateOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(
+		&o.set,
+		"set",
+		nil,
+		`Set a manifest template value as key=value (can be used multiple times, dotted keys set nested values)`,
+	)
+	flags.StringArrayVar(
+		&o.values,
+		"values",
+		nil,
+		`Path to a YAML file with manifest template values (can be used multiple times)`,
+	)
+	flags.StringArrayVar(
+		&o.overlays,
+		"overlay",
+		nil,
+		`Path to an overlay to patch the manifest with, matching machines, tabs, init tasks, etc. by name (can be used multiple times)`,
+	)
+}
+
+// isSet tells whether the manifest file is going to be rendered (templated
+// or patched) rather than used as is.
+func (o *templateOptions) isSet() bool {
+	return o != nil && (len(o.set) > 0 || len(o.values) > 0 || len(o.overlays) > 0)
+}
+
+// render reads the manifest file, executing it as a template if any values
+// are given, and applies the overlays (templated with the same values).
+// Without values, manifests are used as is - init tasks often have {{ }}
+// in their scripts (e.g., docker --format).
+func (o *templateOptions) render(filePath string) ([]byte, error) {
+	data, err := readManifestData(filePath)
+	if err != nil || o == nil {
+		return data, err
+	}
+
+	templated := len(o.set) > 0 || len(o.values) > 0
+
+	values := map[string]any{}
+	for _, path := range o.values {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file: %w", err)
+		}
+
+		var fileValues map[string]any
+		if err := yaml.Unmarshal(raw, &fileValues); err != nil {
+			return nil, fmt.Errorf("failed to parse values file %s: %w", path, err)
+		}
+		labmanifest.MergeValues(values, fileValues)
+	}
+	for _, expr := range o.set {
+		if err := labmanifest.SetValue(values, expr); err != nil {
+			return nil, err
+		}
+	}
+
+	if templated {
+		if data, err = labmanifest.Template(data, values); err != nil {
+			return nil, err
+		}
+	}
+
+	var overlays [][]byte
+	for _, path := range o.overlays {
+		overlay, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay: %w", err)
+		}
+
+		if templated {
+			if overlay, err = labmanifest.Template(overlay, values); err != nil {
+				return nil, fmt.Errorf("overlay %s: %w", path, err)
+			}
+		}
+		overlays = append(overlays, overlay)
+	}
+
+	return labmanifest.Overlay(data, overlays...)
+}
+
+type renderOptions struct {
+	file string
+
+	templateOptions
+}
+
+func newRenderCommand(cli labcli.CLI) *cobra.Command {
+	var opts renderOptions
+
+	cmd := &cobra.Command{
+		Use:     "render [flags]",
+		Short:   "Print the final playground manifest after applying the template values and overlays",
+		Example: renderExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runRender(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		"manifest.yaml",
+		`Path to the playground manifest (template) file (use - to read it from stdin)`,
+	)
+	opts.addFlags(flags)
+
+	return cmd
+}
+
+func runRender(cli labcli.CLI, opts *renderOptions) error {
+	manifest, err := readTemplatedManifestFile(opts.file, &opts.templateOptions)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal manifest: %w", err)
+	}
+
+	cli.PrintOut("%s", data)
+	return nil
+}
@@ -2,10 +2,8 @@ package playground
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -18,6 +16,8 @@ import (
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/ide"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 )
 
@@ -122,34 +122,12 @@ func newRestartCommand(cli labcli.CLI) *cobra.Command {
 }
 
 func runRestartPlayground(ctx context.Context, cli labcli.CLI, opts *restartOptions) error {
-	if api.LooksLikePlayID(opts.playId) {
-		return restartPlay(ctx, cli, opts)
-	}
-
-	// If the provided identifier is not a play ID, search for
-	// a play with matching title and, if found one, restart it.
-	var matches []*api.Play
-
-	cli.PrintAux("Searching for a playground with title: %s\n", opts.playId)
-	plays, err := cli.Client().ListPlays(ctx, api.ListPlaysQueryParams{Persistent: true})
+	playID, err := playref.Resolve(ctx, cli, opts.playId)
 	if err != nil {
-		return fmt.Errorf("couldn't get a list of playgrounds: %w", err)
-	}
-	for _, p := range plays {
-		if strings.HasPrefix(p.Title, opts.playId) {
-			matches = append(matches, p)
-		}
-	}
-
-	if len(matches) == 0 {
-		return errors.New("could not find a play with the given title")
-	}
-
-	if len(matches) > 1 {
-		return errors.New("ambiguous title, please use the full title of a play or a longer prefix")
+		return err
 	}
 
-	opts.playId = matches[0].ID
+	opts.playId = playID
 	return restartPlay(ctx, cli, opts)
 }
 
@@ -161,6 +139,8 @@ func restartPlay(ctx context.Context, cli labcli.CLI, opts *restartOptions) erro
 		return fmt.Errorf("couldn't restart the playground: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionRestart, play)
+
 	s := spinner.New(spinner.CharSets[38], 300*time.Millisecond)
 	s.Writer = cli.AuxStream()
 	s.Prefix = "Waiting for playground to restart... "
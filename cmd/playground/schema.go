@@ -0,0 +1,82 @@
+package playground
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/schema"
+)
+
+const schemaExample = `  # Print the JSON Schema of the playground manifests
+  labctl playground schema > playground.schema.json
+
+  # Install the schema locally and print its path, e.g., to refer to it at the
+  # top of a manifest with: # yaml-language-server: $schema=<path>
+  labctl playground schema --install
+`
+
+type schemaOptions struct {
+	frontMatter bool
+	install     bool
+}
+
+func newSchemaCommand(cli labcli.CLI) *cobra.Command {
+	var opts schemaOptions
+
+	cmd := &cobra.Command{
+		Use:     "schema [flags]",
+		Short:   "Print the JSON Schema of the playground manifests (or of the content front matter)",
+		Example: schemaExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runSchema(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.BoolVar(
+		&opts.frontMatter,
+		"front-matter",
+		false,
+		`Print the schema of the content (challenges, tutorials, etc.) markdown front matter instead`,
+	)
+	flags.BoolVar(
+		&opts.install,
+		"install",
+		false,
+		`Save the schema to the labctl config directory and print its path (for use in yaml-language-server modelines)`,
+	)
+
+	return cmd
+}
+
+func runSchema(cli labcli.CLI, opts *schemaOptions) error {
+	if opts.install {
+		file := schema.PlaygroundManifestFile
+		if opts.frontMatter {
+			file = schema.ContentFrontMatterFile
+		}
+
+		path, err := schema.Install(cli.Config().SchemasDir(), file)
+		if err != nil {
+			return err
+		}
+
+		cli.PrintOut("%s\n", path)
+		return nil
+	}
+
+	generate := schema.PlaygroundManifest
+	if opts.frontMatter {
+		generate = schema.ContentFrontMatter
+	}
+
+	data, err := generate()
+	if err != nil {
+		return err
+	}
+
+	cli.PrintOut("%s", data)
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/ide"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
 	"github.com/iximiuz/labctl/internal/portforward"
 	"github.com/iximiuz/labctl/internal/safety"
 )
@@ -34,6 +35,8 @@ type startOptions struct {
 	ssh  bool
 	ide  string
 
+	templateOptions
+
 	skipWaitRunning bool
 	skipWaitReady   bool
 	skipWaitInit    bool
@@ -51,6 +54,10 @@ type startOptions struct {
 	initConditions map[string]string
 
 	quiet bool
+
+	// projectPlayground is set when starting the current project's playground,
+	// which the project's defaults and port forwards apply to.
+	projectPlayground bool
 }
 
 func newStartCommand(cli labcli.CLI) *cobra.Command {
@@ -64,12 +71,28 @@ func newStartCommand(cli labcli.CLI) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.SetQuiet(opts.quiet)
 
+			project := cli.Config().Project
+			if len(args) == 0 && project.Playground != "" {
+				args = []string{project.Playground}
+			}
+
 			if len(args) == 0 {
 				return labcli.NewStatusError(1,
 					"playground name is required\n\nAvailable playgrounds:\n%s",
 					listKnownPlaygrounds(cmd.Context(), cli))
 			}
 
+			// The project's defaults describe the project's playground only,
+			// and --backend asks for the stock machines.
+			opts.projectPlayground = project.Playground != "" && args[0] == project.Playground
+			if opts.projectPlayground {
+				if !cmd.Flags().Changed("backend") {
+					labcli.ProjectDefault(cmd, "file", &opts.file, project.Manifest)
+				}
+				labcli.ProjectDefault(cmd, "machine", &opts.machine, project.Machine)
+				labcli.ProjectDefault(cmd, "user", &opts.user, project.User)
+			}
+
 			if cmd.Flags().Changed("ide") && opts.ide == "" {
 				opts.ide = ide.VSCode
 			}
@@ -101,6 +124,7 @@ func newStartCommand(cli labcli.CLI) *cobra.Command {
 		"",
 		`Path to a manifest file with playground configuration (machines, tabs, custom init tasks, etc.)`,
 	)
+	opts.addFlags(flags)
 	flags.StringVarP(
 		&opts.machine,
 		"machine",
@@ -188,7 +212,7 @@ func newStartCommand(cli labcli.CLI) *cobra.Command {
 		"init-condition",
 		"i",
 		nil,
-		`Set init conditions as key-value pairs (can be used multiple times)`,
+		`Set init conditions as key-value pairs (can be used multiple times; the missing required ones are prompted for in a terminal)`,
 	)
 	flags.StringVar(
 		&opts.backend,
@@ -208,7 +232,7 @@ func newStartCommand(cli labcli.CLI) *cobra.Command {
 
 func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions) error {
 	var err error
-	opts.safetyDisclaimerConsent, err = showSafetyDisclaimerIfNeeded(ctx, opts.playground, cli, opts.safetyDisclaimerConsent)
+	opts.safetyDisclaimerConsent, err = safety.ShowPlaygroundDisclaimerIfNeeded(ctx, opts.playground, cli, opts.safetyDisclaimerConsent)
 	if err != nil {
 		return err
 	}
@@ -216,16 +240,43 @@ func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions)
 	// Parse manifest file if provided
 	var manifest *api.PlaygroundManifest
 	if opts.file != "" {
-		manifest, err = readManifestFile(opts.file)
+		manifest, err = readTemplatedManifestFile(opts.file, &opts.templateOptions)
 		if err != nil {
 			return fmt.Errorf("couldn't read manifest file: %w", err)
 		}
 	}
 
+	var playground *api.Playground
+	getPlayground := func() (*api.Playground, error) {
+		if playground == nil {
+			if playground, err = cli.Client().GetPlayground(ctx, opts.playground, nil); err != nil {
+				return nil, fmt.Errorf("couldn't get the playground: %w", err)
+			}
+		}
+		return playground, nil
+	}
+
+	// The manifest's init conditions take precedence over the stored ones.
+	var declared []api.InitConditionValue
+	if manifest != nil && len(manifest.Playground.InitConditions.Values) > 0 {
+		declared = manifest.Playground.InitConditions.Values
+	} else {
+		playground, err := getPlayground()
+		if err != nil {
+			return err
+		}
+		declared = playground.InitConditions.Values
+	}
+
+	initConditions, err := resolveInitConditions(cli, declared, opts.initConditions)
+	if err != nil {
+		return err
+	}
+
 	// Build CreatePlay request
 	req := api.CreatePlayRequest{
 		Playground:              opts.playground,
-		InitConditions:          opts.initConditions,
+		InitConditions:          initConditions,
 		SafetyDisclaimerConsent: opts.safetyDisclaimerConsent,
 		AsFreeTierUser:          opts.asFreeTierUser,
 	}
@@ -242,9 +293,9 @@ func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions)
 
 	// Override backend for all machines if requested
 	if opts.backend != "" {
-		playground, err := cli.Client().GetPlayground(ctx, opts.playground, nil)
+		playground, err := getPlayground()
 		if err != nil {
-			return fmt.Errorf("couldn't get the playground: %w", err)
+			return err
 		}
 
 		machines := make([]api.PlaygroundMachine, len(playground.Machines))
@@ -255,11 +306,27 @@ func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions)
 		req.Machines = machines
 	}
 
+	// Check the machine and user before starting anything - a typo
+	// shouldn't leave a running playground behind.
+	machines := req.Machines
+	if len(machines) == 0 {
+		playground, err := getPlayground()
+		if err != nil {
+			return err
+		}
+		machines = playground.Machines
+	}
+	if err := checkMachineAndUser(machines, opts.machine, opts.user); err != nil {
+		return err
+	}
+
 	play, err := cli.Client().CreatePlay(ctx, req)
 	if err != nil {
 		return fmt.Errorf("couldn't start the playground: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStart, play)
+
 	if opts.machine, err = play.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
@@ -269,6 +336,15 @@ func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions)
 
 	cli.PrintAux("New %s playground started with ID %s\n", opts.playground, play.ID)
 
+	// The project's port forwards become the play's "should be forwarded"
+	// ones, so --with-port-forwards and 'labctl port-forward --restore' pick
+	// them up.
+	if opts.projectPlayground {
+		if err := saveProjectPortForwards(ctx, cli, play.ID, opts.machine); err != nil {
+			cli.PrintErr("Warning: couldn't save the project's port forwards: %v\n", err)
+		}
+	}
+
 	// set a title, this should be a non-failure causing request by default
 	if opts.title != "" {
 		var playResponse *api.Play
@@ -379,43 +455,74 @@ func runStartPlayground(ctx context.Context, cli labcli.CLI, opts *startOptions)
 	return nil
 }
 
-func listKnownPlaygrounds(ctx context.Context, cli labcli.CLI) string {
-	playgrounds, err := cli.Client().ListPlaygrounds(ctx, nil)
-	if err != nil {
-		cli.PrintErr("Couldn't list known playgrounds: %v\n", err)
-		return ""
+// checkMachineAndUser checks the explicitly given --machine and --user
+// against the machines the playground is going to have.
+func checkMachineAndUser(machines []api.PlaygroundMachine, machine, user string) error {
+	if len(machines) == 0 || machine == "" {
+		return nil // Nothing to check (against) - the play will tell.
 	}
 
-	var res []string
-	for _, p := range playgrounds {
-		res = append(res, fmt.Sprintf("  - %s - %s", p.Name, p.Description))
+	draft := &api.Play{}
+	for _, m := range machines {
+		draft.Machines = append(draft.Machines, api.Machine{Name: m.Name, Users: m.Users})
 	}
 
-	return strings.Join(res, "\n")
-}
+	if _, err := draft.ResolveMachine(machine); err != nil {
+		return err
+	}
 
-func showSafetyDisclaimerIfNeeded(ctx context.Context, playgroundName string, cli labcli.CLI, consent bool) (bool, error) {
-	if consent {
-		return true, nil
+	// The machines without explicit users get the default ones on start.
+	if user != "" && len(draft.GetMachine(machine).Users) > 0 {
+		if _, err := draft.ResolveUser(machine, user); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	playground, err := cli.Client().GetPlayground(ctx, playgroundName, nil)
-	if err != nil {
-		return false, fmt.Errorf("couldn't get the playground: %w", err)
+func saveProjectPortForwards(ctx context.Context, cli labcli.CLI, playID string, machine string) error {
+	project := cli.Config().Project
+
+	var specs []portforward.ForwardingSpec
+	for _, local := range project.PortForwards.Local {
+		spec, err := portforward.ParseLocal(local)
+		if err != nil {
+			return fmt.Errorf("invalid local port forwarding spec %q in %s", local, project.FilePath)
+		}
+		specs = append(specs, spec)
+	}
+	for _, remote := range project.PortForwards.Remote {
+		spec, err := portforward.ParseRemote(remote)
+		if err != nil {
+			return fmt.Errorf("invalid remote port forwarding spec %q in %s", remote, project.FilePath)
+		}
+		specs = append(specs, spec)
 	}
 
-	if playground.Owner == "" { // official playgrounds don't need consent
-		return true, nil
+	for _, spec := range specs {
+		pf, err := spec.ToPortForward(machine)
+		if err != nil {
+			return err
+		}
+		if err := cli.Client().AddPortForward(ctx, playID, *pf); err != nil {
+			return err
+		}
 	}
 
-	me, err := cli.Client().GetMe(ctx)
+	return nil
+}
+
+func listKnownPlaygrounds(ctx context.Context, cli labcli.CLI) string {
+	playgrounds, err := cli.Client().ListPlaygrounds(ctx, nil)
 	if err != nil {
-		return false, fmt.Errorf("couldn't get the current user info: %w", err)
+		cli.PrintErr("Couldn't list known playgrounds: %v\n", err)
+		return ""
 	}
 
-	if me.ID == playground.Owner {
-		return true, nil
+	var res []string
+	for _, p := range playgrounds {
+		res = append(res, fmt.Sprintf("  - %s - %s", p.Name, p.Description))
 	}
 
-	return safety.ShowSafetyDisclaimer(cli)
+	return strings.Join(res, "\n")
 }
@@ -0,0 +1,24 @@
+package playground
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func TestCheckMachineAndUser(t *testing.T) {
+	machines := []api.PlaygroundMachine{
+		{Name: "node-01", Users: []api.MachineUser{{Name: "laborant", Default: true}}},
+		{Name: "node-02"},
+	}
+
+	assert.NoError(t, checkMachineAndUser(machines, "", ""))
+	assert.NoError(t, checkMachineAndUser(machines, "node-01", "laborant"))
+	assert.NoError(t, checkMachineAndUser(machines, "node-02", "whoever"))
+	assert.NoError(t, checkMachineAndUser(nil, "node-03", ""))
+
+	assert.EqualError(t, checkMachineAndUser(machines, "node-03", ""), `machine "node-03" not found in the playground`)
+	assert.EqualError(t, checkMachineAndUser(machines, "node-01", "nobody"), `user "nobody" not found in the machine "node-01"`)
+}
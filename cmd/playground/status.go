@@ -15,6 +15,7 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 type statusOptions struct {
@@ -40,7 +41,11 @@ func newStatusCommand(cli labcli.CLI) *cobra.Command {
 			return opts.validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runStatus(cmd.Context(), cli, args[0], &opts))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runStatus(cmd.Context(), cli, playID, &opts))
 		},
 	}
 
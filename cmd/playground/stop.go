@@ -8,8 +8,11 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 const stopCommandTimeout = 5 * time.Minute
@@ -17,6 +20,8 @@ const stopCommandTimeout = 5 * time.Minute
 type stopOptions struct {
 	playID string
 
+	bulk bulkOptions
+
 	quiet bool
 }
 
@@ -26,12 +31,39 @@ func newStopCommand(cli labcli.CLI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "stop [flags] <playground-id>",
 		Short:             `Stop a running playground session, preserving its state for future use`,
-		Args:              cobra.ExactArgs(1),
+		Example:           `  labctl playground stop --all --filter playground=k3s --older-than 2h`,
+		Args:              opts.bulk.args(cobra.ExactArgs(1), cobra.ExactArgs(0)),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.SetQuiet(opts.quiet)
 
-			opts.playID = args[0]
+			if err := opts.bulk.validate(cmd); err != nil {
+				return err
+			}
+
+			if opts.bulk.all {
+				return labcli.WrapStatusError(runBulk(cmd.Context(), cli, &opts.bulk, bulkAction{
+					verb:     "stop",
+					past:     "stopped",
+					eligible: (*api.Play).IsActive,
+					run: func(ctx context.Context, play *api.Play) error {
+						stopped, err := cli.Client().StopPlay(ctx, play.ID)
+						if err != nil {
+							return err
+						}
+						ledger.Record(cli, ledger.ActionStop, stopped)
+						return waitPlay(ctx, cli, play.ID, stopCommandTimeout, func(p *api.Play) bool {
+							return !p.IsActive()
+						})
+					},
+				}))
+			}
+
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 
 			return labcli.WrapStatusError(runStopPlayground(cmd.Context(), cli, &opts))
 		},
@@ -39,6 +71,8 @@ func newStopCommand(cli labcli.CLI) *cobra.Command {
 
 	flags := cmd.Flags()
 
+	opts.bulk.addFlags(flags, "Stop")
+
 	flags.BoolVarP(
 		&opts.quiet,
 		"quiet",
@@ -53,10 +87,13 @@ func newStopCommand(cli labcli.CLI) *cobra.Command {
 func runStopPlayground(ctx context.Context, cli labcli.CLI, opts *stopOptions) error {
 	cli.PrintAux("Stopping playground %s...\n", opts.playID)
 
-	if _, err := cli.Client().StopPlay(ctx, opts.playID); err != nil {
+	play, err := cli.Client().StopPlay(ctx, opts.playID)
+	if err != nil {
 		return fmt.Errorf("couldn't stop the playground: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStop, play)
+
 	s := spinner.New(spinner.CharSets[38], 300*time.Millisecond)
 	s.Writer = cli.AuxStream()
 	s.Prefix = "Waiting for playground to stop... "
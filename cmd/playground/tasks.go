@@ -14,10 +14,26 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
+	"github.com/iximiuz/labctl/internal/taskgraph"
 )
 
+const tasksExample = `  # List the tasks of a playground
+  labctl playground tasks 65e7f3d0c5e5e2bd1a0f6c4e
+
+  # Wait for the init tasks to complete
+  labctl playground tasks 65e7f3d0c5e5e2bd1a0f6c4e --kind init --wait
+
+  # Show which tasks wait for which, and what takes the longest to initialize
+  labctl playground tasks 65e7f3d0c5e5e2bd1a0f6c4e --graph
+
+  # Render the task graph with Graphviz
+  labctl playground tasks 65e7f3d0c5e5e2bd1a0f6c4e --graph -o dot | dot -Tsvg > tasks.svg
+`
+
 type tasksOptions struct {
 	output   string
+	graph    bool
 	wait     bool
 	failFast bool
 	timeout  time.Duration
@@ -25,7 +41,14 @@ type tasksOptions struct {
 }
 
 func (opts *tasksOptions) validate() error {
-	if opts.output != "table" && opts.output != "json" && opts.output != "yaml" && opts.output != "name" && opts.output != "none" {
+	if opts.graph {
+		if opts.output == "table" {
+			opts.output = "ascii"
+		}
+		if err := validateGraphFormat(opts.output); err != nil {
+			return err
+		}
+	} else if opts.output != "table" && opts.output != "json" && opts.output != "yaml" && opts.output != "name" && opts.output != "none" {
 		return fmt.Errorf("invalid output format: %s (supported formats: table, json, yaml, name, none)", opts.output)
 	}
 
@@ -42,13 +65,18 @@ func newTasksCommand(cli labcli.CLI) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "tasks <play-id>",
 		Short:             "List tasks of a playground session",
+		Example:           tasksExample,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.NonDestroyedPlays(cli),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runListTasks(cmd.Context(), cli, args[0], &opts))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			return labcli.WrapStatusError(runListTasks(cmd.Context(), cli, playID, &opts))
 		},
 	}
 
@@ -59,7 +87,14 @@ func newTasksCommand(cli labcli.CLI) *cobra.Command {
 		"output",
 		"o",
 		"table",
-		"Output format: table, json, yaml, name, none",
+		"Output format: table, json, yaml, name, none (with --graph: ascii, dot, mermaid)",
+	)
+
+	flags.BoolVar(
+		&opts.graph,
+		"graph",
+		false,
+		"Show the task dependency graph with the task statuses and the critical path",
 	)
 
 	flags.BoolVar(
@@ -195,12 +230,21 @@ func runListTasks(ctx context.Context, cli labcli.CLI, playgroundID string, opts
 			byName[task.Name] = task
 		}
 
-		printer := newTaskListPrinter(cli.OutputStream(), opts.output)
+		if opts.graph {
+			graph, err := taskgraph.FromPlayTasks(filterTasksByKind(byName, opts.kind))
+			if err != nil {
+				return err
+			}
+
+			cli.PrintOut("%s", renderGraph(graph, opts.output, cli.OutputStream().IsTerminal()))
+		} else {
+			printer := newTaskListPrinter(cli.OutputStream(), opts.output)
 
-		if err := printer.Print(filterTasksByKind(byName, opts.kind)); err != nil {
-			return err
+			if err := printer.Print(filterTasksByKind(byName, opts.kind)); err != nil {
+				return err
+			}
+			printer.Flush()
 		}
-		printer.Flush()
 	}
 
 	if waitErr != nil {
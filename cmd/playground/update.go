@@ -6,12 +6,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/moby/term"
 	"github.com/spf13/cobra"
 
 	"github.com/iximiuz/labctl/api"
 	clicontent "github.com/iximiuz/labctl/cmd/content"
 	"github.com/iximiuz/labctl/content"
 	"github.com/iximiuz/labctl/internal/labcli"
+	labmanifest "github.com/iximiuz/labctl/internal/manifest"
 )
 
 type updateOptions struct {
@@ -19,9 +21,13 @@ type updateOptions struct {
 
 	clicontent.DirOptions
 
+	templateOptions
+
 	quiet bool
 
 	force bool
+
+	diff bool
 }
 
 func newUpdateCommand(cli labcli.CLI) *cobra.Command {
@@ -64,6 +70,13 @@ func newUpdateCommand(cli labcli.CLI) *cobra.Command {
 		false,
 		"Overwrite existing remote files with the local ones and delete remote files that don't exist locally without confirmation",
 	)
+	flags.BoolVar(
+		&opts.diff,
+		"diff",
+		false,
+		"Show the changes to the playground manifest and ask for confirmation before applying them",
+	)
+	opts.addFlags(flags)
 	opts.DirOptions.AddDirFlag(
 		flags,
 		"Local directory with the manifest YAML file and possibly other content files to accompany the playground (default: $CWD/<playground-name>)",
@@ -108,29 +121,39 @@ func runUpdate(ctx context.Context, cli labcli.CLI, name string, opts *updateOpt
 		cli.PrintAux("Updating playground %s from stdin\n", name)
 	}
 
-	manifest, err := readManifestFile(opts.file)
+	manifest, err := readValidManifestFile(opts.file, &opts.templateOptions)
 	if err != nil {
 		return fmt.Errorf("couldn't read manifest: %w", err)
 	}
 
-	if !manifest.Playground.HasAccessControl() && manifest.Playground.Access != nil && manifest.Playground.Access.Mode != "" {
-		switch manifest.Playground.Access.Mode {
-		case "private":
-			// For backward compatibility
-			manifest.Playground.AccessControl = api.PlaygroundAccessControl{
-				CanList:  []string{"owner"},
-				CanRead:  []string{"owner"},
-				CanStart: []string{"owner"},
-			}
-		case "public":
-			// For backward compatibility
-			manifest.Playground.AccessControl = api.PlaygroundAccessControl{
-				CanList:  []string{"anyone"},
-				CanRead:  []string{"anyone"},
-				CanStart: []string{"anyone"},
+	if err := applyLegacyAccessMode(manifest); err != nil {
+		return err
+	}
+
+	if opts.diff {
+		if !cli.InputStream().IsTerminal() {
+			return labcli.NewStatusError(1, "--diff requires an interactive terminal to confirm the changes (use 'labctl playground diff' to only preview them)")
+		}
+
+		remote, err := getManifest(ctx, cli, name)
+		if err != nil {
+			return err
+		}
+
+		diff, err := labmanifest.UnifiedDiff(updatableManifest(remote), updatableManifest(manifest), name, manifestFileName(opts.file))
+		if err != nil {
+			return fmt.Errorf("couldn't compare the manifests: %w", err)
+		}
+
+		if diff == "" {
+			cli.PrintAux("No changes in the manifest.\n")
+		} else {
+			_, color := term.GetFdInfo(cli.ErrorStream())
+			cli.PrintErr("%s", colorizeDiff(diff, color))
+
+			if !cli.Confirm("Apply these changes?", "Yes", "No") {
+				return labcli.NewStatusError(0, "Nothing changed.")
 			}
-		default:
-			return fmt.Errorf("unsupported access mode: %s (only 'private' and 'public' are supported)", manifest.Playground.Access.Mode)
 		}
 	}
 
@@ -172,3 +195,32 @@ func runUpdate(ctx context.Context, cli labcli.CLI, name string, opts *updateOpt
 	cli.PrintOut("%s\n", playground.Name)
 	return nil
 }
+
+// applyLegacyAccessMode turns the deprecated access mode into the access
+// control settings, unless they are set explicitly.
+func applyLegacyAccessMode(manifest *api.PlaygroundManifest) error {
+	if manifest.Playground.HasAccessControl() || manifest.Playground.Access == nil || manifest.Playground.Access.Mode == "" {
+		return nil
+	}
+
+	switch manifest.Playground.Access.Mode {
+	case "private":
+		// For backward compatibility
+		manifest.Playground.AccessControl = api.PlaygroundAccessControl{
+			CanList:  []string{"owner"},
+			CanRead:  []string{"owner"},
+			CanStart: []string{"owner"},
+		}
+	case "public":
+		// For backward compatibility
+		manifest.Playground.AccessControl = api.PlaygroundAccessControl{
+			CanList:  []string{"anyone"},
+			CanRead:  []string{"anyone"},
+			CanStart: []string{"anyone"},
+		}
+	default:
+		return fmt.Errorf("unsupported access mode: %s (only 'private' and 'public' are supported)", manifest.Playground.Access.Mode)
+	}
+
+	return nil
+}
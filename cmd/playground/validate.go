@@ -0,0 +1,54 @@
+package playground
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+type validateOptions struct {
+	file string
+
+	templateOptions
+}
+
+func newValidateCommand(cli labcli.CLI) *cobra.Command {
+	var opts validateOptions
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags]",
+		Short: "Check a playground manifest file for errors without sending it to the server",
+		Long: `Check a playground manifest file for errors without sending it to the server.
+
+The checks cover network subnets and interface addresses, references to machines,
+networks, and init tasks, init task dependency cycles, duplicate names, machine
+backends, RAM and drive sizes, and drive mount points. 'labctl playground create'
+and 'labctl playground update' run the same checks before sending the manifest.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runValidate(cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		"manifest.yaml",
+		`Path to playground manifest file (use - to read it from stdin)`,
+	)
+	opts.addFlags(flags)
+
+	return cmd
+}
+
+func runValidate(cli labcli.CLI, opts *validateOptions) error {
+	if _, err := readValidManifestFile(opts.file, &opts.templateOptions); err != nil {
+		return err
+	}
+
+	cli.PrintAux("%s is valid.\n", manifestFileName(opts.file))
+	return nil
+}
@@ -12,13 +12,32 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 const waitPlaygroundTimeout = 48 * time.Hour
 
+const waitExample = `  # Wait for all playground tasks to complete
+  labctl playground wait 65e78a64366c2b0cf9ddc34c
+
+  # Wait for a specific task and an open port
+  labctl playground wait 65e78a64366c2b0cf9ddc34c \
+    --for task/install_k8s=completed --for port/node-01:8080=open --timeout 10m
+
+  # Wait until a command succeeds on a machine
+  labctl playground wait 65e78a64366c2b0cf9ddc34c --for 'exec/node-01="kubectl get nodes"'
+
+  # Wait until the playground is either running or has failed
+  labctl playground wait 65e78a64366c2b0cf9ddc34c --for state=RUNNING --for state=FAILED --any
+`
+
 type waitOptions struct {
 	initOnly bool
 	timeout  time.Duration
+
+	conditions []string
+	anyOf      bool
+	interval   time.Duration
 }
 
 func newWaitCommand(cli labcli.CLI) *cobra.Command {
@@ -26,11 +45,25 @@ func newWaitCommand(cli labcli.CLI) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:               "wait [flags] <play-id>",
-		Short:             "Wait until a playground's tasks are completed",
+		Short:             "Wait until a playground's tasks are completed (or the given conditions are met)",
+		Example:           waitExample,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completion.NonDestroyedPlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return labcli.WrapStatusError(runWaitPlayground(cmd.Context(), cli, args[0], &opts))
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			if len(opts.conditions) > 0 {
+				if opts.initOnly {
+					return labcli.NewStatusError(1, "--init-only and --for are mutually exclusive")
+				}
+				return labcli.WrapStatusError(runWaitConditions(cmd.Context(), cli, playID, &opts))
+			}
+			if opts.anyOf {
+				return labcli.NewStatusError(1, "--any requires --for")
+			}
+			return labcli.WrapStatusError(runWaitPlayground(cmd.Context(), cli, playID, &opts))
 		},
 	}
 
@@ -50,6 +83,25 @@ func newWaitCommand(cli labcli.CLI) *cobra.Command {
 		"Maximum time to wait for the tasks to complete (0 to wait indefinitely)",
 	)
 
+	flags.StringArrayVar(
+		&opts.conditions,
+		"for",
+		nil,
+		"Wait for a condition instead (can be repeated): "+waitConditionsHelp,
+	)
+	flags.BoolVar(
+		&opts.anyOf,
+		"any",
+		false,
+		"With --for: return as soon as any of the conditions is met (instead of all of them)",
+	)
+	flags.DurationVar(
+		&opts.interval,
+		"interval",
+		defaultWaitInterval,
+		"With --for: how often to check the conditions",
+	)
+
 	return cmd
 }
 
@@ -87,3 +139,32 @@ func runWaitPlayground(ctx context.Context, cli labcli.CLI, playID string, opts
 
 	return nil
 }
+
+func runWaitConditions(ctx context.Context, cli labcli.CLI, playID string, opts *waitOptions) error {
+	conds, err := parseWaitConditions(opts.conditions)
+	if err != nil {
+		return err
+	}
+
+	if opts.interval <= 0 {
+		return labcli.NewStatusError(1, "--interval must be positive")
+	}
+
+	spin := spinner.New(spinner.CharSets[38], 300*time.Millisecond)
+	spin.Writer = cli.AuxStream()
+	spin.Prefix = "Waiting for " + joinWaitConditions(conds) + " "
+	spin.Start()
+
+	met, err := waitConditions(ctx, cli, playID, conds, opts.anyOf, opts.timeout, opts.interval, func(pending []*waitCondition) {
+		spin.Prefix = "Waiting for " + joinWaitConditions(pending) + " "
+	})
+
+	spin.Stop()
+
+	if err != nil {
+		return err
+	}
+
+	cli.PrintAux("Done waiting for playground: %s.\n", joinWaitConditions(met))
+	return nil
+}
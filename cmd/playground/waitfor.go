@@ -0,0 +1,370 @@
+package playground
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/cmd/ssh"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+const defaultWaitInterval = 5 * time.Second
+
+type waitConditionKind string
+
+const (
+	waitForState   waitConditionKind = "state"
+	waitForMachine waitConditionKind = "machine"
+	waitForTask    waitConditionKind = "task"
+	waitForPort    waitConditionKind = "port"
+	waitForExec    waitConditionKind = "exec"
+)
+
+var waitConditionKinds = []waitConditionKind{waitForState, waitForMachine, waitForTask, waitForPort, waitForExec}
+
+const waitConditionsHelp = `state=<STATE>, machine/<name>=running|ready|stopped, task/<name>=completed|failed,
+port/<machine>:<port>=open|closed, or exec/<machine>="<command>"`
+
+var (
+	waitMachineValues = []string{"running", "ready", "stopped"}
+	waitTaskValues    = []string{"completed", "failed"}
+	waitPortValues    = []string{"open", "closed"}
+)
+
+// waitCondition is a single --for expression, e.g., task/install_k8s=completed.
+type waitCondition struct {
+	expr string
+
+	kind    waitConditionKind
+	machine string // machine, port, and exec conditions
+	task    string
+	port    int
+	value   string // state, machine and task status, or the exec command
+}
+
+func parseWaitCondition(expr string) (*waitCondition, error) {
+	key, value, ok := strings.Cut(expr, "=")
+	if !ok || key == "" || value == "" {
+		return nil, fmt.Errorf("invalid condition %q: expected <kind>[/<target>]=<value>", expr)
+	}
+
+	kind, target, hasTarget := strings.Cut(key, "/")
+	cond := &waitCondition{
+		expr: expr,
+		kind: waitConditionKind(kind),
+	}
+
+	if !slices.Contains(waitConditionKinds, cond.kind) {
+		return nil, fmt.Errorf("invalid condition %q: unknown kind %q (supported conditions: %s)",
+			expr, kind, strings.Join(strings.Fields(waitConditionsHelp), " "))
+	}
+	if cond.kind != waitForState && target == "" {
+		return nil, fmt.Errorf("invalid condition %q: %s requires a target (e.g., %s/<name>=...)", expr, kind, kind)
+	}
+
+	switch cond.kind {
+	case waitForState:
+		if hasTarget {
+			return nil, fmt.Errorf("invalid condition %q: state takes no target", expr)
+		}
+
+		state := api.PlayState(strings.ToUpper(value))
+		if !slices.Contains(playStates, state) {
+			return nil, fmt.Errorf("invalid condition %q: unknown playground state (supported states: %s)", expr, joinPlayStates())
+		}
+		cond.value = string(state)
+
+	case waitForMachine:
+		cond.machine = target
+		cond.value = strings.ToLower(value)
+		if !slices.Contains(waitMachineValues, cond.value) {
+			return nil, fmt.Errorf("invalid condition %q: expected one of %s", expr, strings.Join(waitMachineValues, ", "))
+		}
+
+	case waitForTask:
+		cond.task = target
+		cond.value = strings.ToLower(value)
+		if !slices.Contains(waitTaskValues, cond.value) {
+			return nil, fmt.Errorf("invalid condition %q: expected one of %s", expr, strings.Join(waitTaskValues, ", "))
+		}
+
+	case waitForPort:
+		machine, port, ok := strings.Cut(target, ":")
+		if !ok || machine == "" {
+			return nil, fmt.Errorf("invalid condition %q: expected port/<machine>:<port>", expr)
+		}
+
+		num, err := strconv.Atoi(port)
+		if err != nil || num <= 0 || num > 65535 {
+			return nil, fmt.Errorf("invalid condition %q: invalid port number %q", expr, port)
+		}
+
+		cond.machine = machine
+		cond.port = num
+		cond.value = strings.ToLower(value)
+		if !slices.Contains(waitPortValues, cond.value) {
+			return nil, fmt.Errorf("invalid condition %q: expected one of %s", expr, strings.Join(waitPortValues, ", "))
+		}
+
+	case waitForExec:
+		cond.machine = target
+		cond.value = unquote(value)
+		if cond.value == "" {
+			return nil, fmt.Errorf("invalid condition %q: empty command", expr)
+		}
+	}
+
+	return cond, nil
+}
+
+func parseWaitConditions(exprs []string) ([]*waitCondition, error) {
+	var conds []*waitCondition
+	for _, expr := range exprs {
+		cond, err := parseWaitCondition(expr)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func (c *waitCondition) String() string {
+	return c.expr
+}
+
+// validate catches the conditions that can never be met in the given play,
+// e.g., because of a typo in a machine or task name.
+func (c *waitCondition) validate(play *api.Play) error {
+	if c.machine != "" && play.GetMachine(c.machine) == nil {
+		return fmt.Errorf("condition %s: machine %q not found in the playground", c, c.machine)
+	}
+	if c.task != "" {
+		if _, ok := play.Tasks[c.task]; !ok {
+			return fmt.Errorf("condition %s: task %q not found in the playground", c, c.task)
+		}
+	}
+	return nil
+}
+
+// waitProbe evaluates conditions against the latest state of the play. The
+// port scans are cached for the duration of one round of checks.
+type waitProbe struct {
+	cli  labcli.CLI
+	play *api.Play
+
+	scans map[string][]*api.ScannedPort
+}
+
+func (p *waitProbe) check(ctx context.Context, c *waitCondition) (bool, error) {
+	switch c.kind {
+	case waitForState:
+		return p.play.StateIs(api.PlayState(c.value)), nil
+
+	case waitForMachine:
+		switch c.value {
+		case "ready":
+			return p.play.MachineReady(c.machine), nil
+		case "running":
+			return p.play.MachineState(c.machine) == api.MachineStateRunning, nil
+		default:
+			return p.play.MachineState(c.machine) == api.MachineStateStopped, nil
+		}
+
+	case waitForTask:
+		task := p.play.Tasks[c.task]
+		if c.value == "completed" && task.Status == api.PlayTaskStatusFailed {
+			return false, fmt.Errorf("condition %s can't be met: task %q failed", c, c.task)
+		}
+		if c.value == "completed" {
+			return task.Status == api.PlayTaskStatusCompleted, nil
+		}
+		return task.Status == api.PlayTaskStatusFailed, nil
+
+	case waitForPort:
+		if !p.play.StateIs(api.StateRunning) {
+			return c.value == "closed", nil
+		}
+
+		ports, err := p.scan(ctx, c.machine)
+		if err != nil {
+			slog.Debug("Couldn't scan playground ports", "machine", c.machine, "error", err.Error())
+			return false, nil
+		}
+
+		open := slices.ContainsFunc(ports, func(port *api.ScannedPort) bool {
+			return port.Number == c.port
+		})
+		return open == (c.value == "open"), nil
+
+	case waitForExec:
+		if !p.play.MachineReady(c.machine) {
+			return false, nil
+		}
+		return p.exec(ctx, c), nil
+	}
+
+	return false, fmt.Errorf("unknown condition kind %q", c.kind)
+}
+
+func (p *waitProbe) scan(ctx context.Context, machine string) ([]*api.ScannedPort, error) {
+	if ports, ok := p.scans[machine]; ok {
+		return ports, nil
+	}
+
+	ports, err := p.cli.Client().ScanPorts(ctx, p.play.ID, machine)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.scans == nil {
+		p.scans = map[string][]*api.ScannedPort{}
+	}
+	p.scans[machine] = ports
+	return ports, nil
+}
+
+func (p *waitProbe) exec(ctx context.Context, c *waitCondition) bool {
+	user, err := p.play.ResolveUser(c.machine, "")
+	if err != nil {
+		return false
+	}
+
+	// Polled repeatedly while the spinner is on, so the command must not
+	// touch the terminal - only its exit status matters.
+	out, err := ssh.RunSSHCommand(ctx, p.cli, p.play, c.machine, user, c.value)
+	if err != nil {
+		slog.Debug("Condition command failed", "condition", c.String(), "error", err.Error(), "output", string(out))
+		return false
+	}
+
+	return true
+}
+
+// waitConditions polls the play until all (or, with anyOf, at least one) of the
+// conditions hold in the same round of checks.
+func waitConditions(
+	ctx context.Context,
+	cli labcli.CLI,
+	playID string,
+	conds []*waitCondition,
+	anyOf bool,
+	timeout time.Duration,
+	interval time.Duration,
+	progress func(pending []*waitCondition),
+) ([]*waitCondition, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	validated := false
+
+	for {
+		play, err := cli.Client().GetPlay(ctx, playID)
+		if errors.Is(err, api.ErrNotFound) {
+			return nil, fmt.Errorf("playground %s not found", playID)
+		}
+
+		if err == nil {
+			if !validated {
+				for _, c := range conds {
+					if err := c.validate(play); err != nil {
+						return nil, err
+					}
+				}
+				validated = true
+			}
+
+			met, pending, err := checkConditions(ctx, &waitProbe{cli: cli, play: play}, conds, anyOf)
+			if err != nil {
+				return nil, err
+			}
+			if met != nil {
+				return met, nil
+			}
+
+			if play.StateIs(api.StateDestroyed) || play.StateIs(api.StateFailed) {
+				return nil, fmt.Errorf("playground %s is %s", playID, strings.ToLower(string(play.State())))
+			}
+
+			progress(pending)
+		} else if ctx.Err() == nil {
+			slog.Debug("Couldn't get the playground", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("timed out waiting for the playground")
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkConditions returns the conditions that made the wait succeed (nil if
+// it didn't) and the ones that are still pending. Evaluation stops as soon as
+// the outcome is known, so that no extra SSH sessions or port scans are made.
+// With anyOf, a condition that can't be met anymore only fails the wait if
+// none of the others can be met either.
+func checkConditions(
+	ctx context.Context,
+	probe *waitProbe,
+	conds []*waitCondition,
+	anyOf bool,
+) (met []*waitCondition, pending []*waitCondition, err error) {
+	var unmeetable []error
+
+	for i, c := range conds {
+		ok, err := probe.check(ctx, c)
+		if err != nil && anyOf {
+			unmeetable = append(unmeetable, err)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case ok && anyOf:
+			return []*waitCondition{c}, nil, nil
+		case !ok && !anyOf:
+			return nil, conds[i:], nil
+		case !ok:
+			pending = append(pending, c)
+		}
+	}
+
+	if anyOf && len(unmeetable) == len(conds) {
+		return nil, nil, errors.Join(unmeetable...)
+	}
+	if anyOf {
+		return nil, pending, nil
+	}
+	return conds, nil, nil
+}
+
+func joinWaitConditions(conds []*waitCondition) string {
+	var exprs []string
+	for _, c := range conds {
+		exprs = append(exprs, c.String())
+	}
+	return strings.Join(exprs, ", ")
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
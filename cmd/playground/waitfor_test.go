@@ -0,0 +1,117 @@
+package playground
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/api/apitest"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	for expr, want := range map[string]waitCondition{
+		"state=running":                    {kind: waitForState, value: "RUNNING"},
+		"machine/node-01=Ready":            {kind: waitForMachine, machine: "node-01", value: "ready"},
+		"task/install_k8s=completed":       {kind: waitForTask, task: "install_k8s", value: "completed"},
+		"port/node-01:8080=open":           {kind: waitForPort, machine: "node-01", port: 8080, value: "open"},
+		`exec/node-01="kubectl get nodes"`: {kind: waitForExec, machine: "node-01", value: "kubectl get nodes"},
+		"exec/node-01=test -f /tmp/a=b":    {kind: waitForExec, machine: "node-01", value: "test -f /tmp/a=b"},
+	} {
+		cond, err := parseWaitCondition(expr)
+		if assert.NoError(t, err, expr) {
+			want.expr = expr
+			assert.Equal(t, want, *cond, expr)
+		}
+	}
+
+	for _, expr := range []string{
+		"",
+		"state",
+		"state=SLEEPING",
+		"state/node-01=RUNNING",
+		"machine=ready",
+		"machine/node-01=happy",
+		"task/=completed",
+		"port/node-01=open",
+		"port/node-01:http=open",
+		"port/node-01:70000=open",
+		`exec/node-01=""`,
+		"disk/node-01=full",
+	} {
+		_, err := parseWaitCondition(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestWaitConditions(t *testing.T) {
+	ctx := context.Background()
+
+	s := apitest.NewServerWithOptions(apitest.Options{HoldInitTasks: true})
+	t.Cleanup(s.Close)
+
+	s.AddPlayground(api.Playground{
+		Name:     "k3s",
+		Machines: []api.PlaygroundMachine{{Name: "node-01"}},
+		InitTasks: map[string]api.InitTask{
+			"install_k8s": {Name: "install_k8s", Init: true},
+		},
+	})
+
+	cli := labcli.NewCLI(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard, "test")
+	cli.SetClient(s.Client())
+
+	play, err := s.Client().CreatePlay(ctx, api.CreatePlayRequest{Playground: "k3s"})
+	require.NoError(t, err)
+
+	wait := func(anyOf bool, exprs ...string) ([]*waitCondition, error) {
+		conds, err := parseWaitConditions(exprs)
+		require.NoError(t, err)
+		return waitConditions(ctx, cli, play.ID, conds, anyOf, 200*time.Millisecond, 10*time.Millisecond, func([]*waitCondition) {})
+	}
+
+	met, err := wait(false, "state=RUNNING", "machine/node-01=ready")
+	require.NoError(t, err)
+	assert.Equal(t, "state=RUNNING, machine/node-01=ready", joinWaitConditions(met))
+
+	// All conditions must hold.
+	_, err = wait(false, "state=RUNNING", "task/install_k8s=completed")
+	assert.ErrorContains(t, err, "timed out")
+
+	// Any condition is enough.
+	met, err = wait(true, "task/install_k8s=completed", "port/node-01:8080=closed")
+	require.NoError(t, err)
+	assert.Equal(t, "port/node-01:8080=closed", joinWaitConditions(met))
+
+	s.SetOpenPorts(play.ID, "node-01", 8080)
+	require.NoError(t, s.SetTaskStatus(play.ID, "install_k8s", api.PlayTaskStatusCompleted))
+
+	_, err = wait(false, "task/install_k8s=completed", "port/node-01:8080=open")
+	require.NoError(t, err)
+
+	// Typos fail fast instead of waiting for the timeout.
+	_, err = wait(false, "machine/node-02=ready")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = wait(false, "task/install_k9s=completed")
+	assert.ErrorContains(t, err, "not found")
+
+	// Failed tasks can't complete anymore.
+	require.NoError(t, s.SetTaskStatus(play.ID, "install_k8s", api.PlayTaskStatusFailed))
+	_, err = wait(false, "task/install_k8s=completed")
+	assert.ErrorContains(t, err, "failed")
+
+	// ...but that's fine as long as another alternative holds.
+	met, err = wait(true, "task/install_k8s=completed", "task/install_k8s=failed")
+	require.NoError(t, err)
+	assert.Equal(t, "task/install_k8s=failed", joinWaitConditions(met))
+
+	_, err = wait(true, "task/install_k8s=completed")
+	assert.ErrorContains(t, err, "failed")
+}
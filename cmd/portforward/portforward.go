@@ -4,17 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
+	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/keepalive"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 )
 
 // runRestorePortForwards restores saved port forwards and blocks until done.
 func runRestorePortForwards(ctx context.Context, cli labcli.CLI, opts *options) error {
+	if opts.keepalive {
+		defer keepalive.StartSession(ctx, cli, opts.playID)()
+	}
+
 	resultCh, err := portforward.RestoreSavedForwards(ctx, cli.Client(), opts.playID, cli)
 	if err != nil {
 		return err
@@ -35,6 +43,12 @@ type options struct {
 
 	quiet bool
 
+	keepalive bool
+
+	// projectDefaults fills in the flags from the project config once the
+	// play is known (see applyProjectDefaults).
+	projectDefaults func(play *api.Play)
+
 	// New flags
 	list    bool
 	restore bool
@@ -72,9 +86,20 @@ The command also supports managing "should be forwarded" ports:
 When using -L|-R flags, port forwards are automatically saved to the playground's config for later restoration.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 			cli.SetQuiet(opts.quiet)
 
+			project := cli.Config().Project
+			opts.projectDefaults = func(play *api.Play) {
+				if labcli.IsProjectPlay(project, play) {
+					labcli.ProjectDefault(cmd, "machine", &opts.machine, project.Machine)
+				}
+			}
+
 			// Handle list mode
 			if opts.list {
 				return labcli.WrapStatusError(runListPortForwards(cmd.Context(), cli, &opts))
@@ -91,6 +116,16 @@ When using -L|-R flags, port forwards are automatically saved to the playground'
 			}
 
 			// Regular port forwarding mode
+			if len(opts.locals)+len(opts.remotes) == 0 && len(project.PortForwards.Local)+len(project.PortForwards.Remote) > 0 {
+				play, err := cli.Client().GetPlay(cmd.Context(), opts.playID)
+				if err != nil {
+					return labcli.WrapStatusError(fmt.Errorf("couldn't get playground: %w", err))
+				}
+				if labcli.IsProjectPlay(project, play) {
+					opts.locals = project.PortForwards.Local
+					opts.remotes = project.PortForwards.Remote
+				}
+			}
 			if len(opts.locals)+len(opts.remotes) == 0 {
 				return labcli.NewStatusError(1, "at least one -L or -R flag must be provided (or use --list, --restore, --remove)")
 			}
@@ -163,6 +198,7 @@ When using -L|-R flags, port forwards are automatically saved to the playground'
 		-1,
 		`Remove a "should be forwarded" port from the playground's config by index (0-based)`,
 	)
+	keepalive.AddFlag(flags, &opts.keepalive)
 
 	return cmd
 }
@@ -228,11 +264,25 @@ func runPortForward(ctx context.Context, cli labcli.CLI, opts *options) error {
 		return fmt.Errorf("couldn't get playground: %w", err)
 	}
 
+	if opts.projectDefaults != nil {
+		opts.projectDefaults(p)
+	}
+
 	if opts.machine, err = p.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
 
-	// Save port forwards to play's config
+	if opts.keepalive {
+		defer keepalive.StartSession(ctx, cli, p.ID)()
+	}
+
+	// Save port forwards to play's config (unless already there, e.g.,
+	// from the project's .labctl.yaml).
+	saved, err := cli.Client().ListPortForwards(ctx, p.ID)
+	if err != nil {
+		cli.PrintErr("Warning: couldn't list saved port forwards: %v\n", err)
+	}
+
 	allSpecs := []portforward.ForwardingSpec{}
 	allSpecs = append(allSpecs, opts.localsParsed...)
 	allSpecs = append(allSpecs, opts.remotesParsed...)
@@ -241,6 +291,9 @@ func runPortForward(ctx context.Context, cli labcli.CLI, opts *options) error {
 		if err != nil {
 			return fmt.Errorf("couldn't convert port forwarding spec to API port forward model: %w", err)
 		}
+		if slices.ContainsFunc(saved, func(s *api.PortForward) bool { return *s == *pf }) {
+			continue
+		}
 		if err := cli.Client().AddPortForward(ctx, p.ID, *pf); err != nil {
 			cli.PrintErr("Warning: couldn't save port forward: %v\n", err)
 		}
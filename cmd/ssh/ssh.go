@@ -15,7 +15,10 @@ import (
 
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/internal/completion"
+	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/keepalive"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 	"github.com/iximiuz/labctl/internal/retry"
 	"github.com/iximiuz/labctl/internal/ssh"
@@ -38,6 +41,12 @@ type options struct {
 	command []string
 
 	forwardAgent bool
+
+	keepalive bool
+
+	// projectDefaults fills in the flags from the project config once the
+	// play is known (see applyProjectDefaults).
+	projectDefaults func(play *api.Play)
 }
 
 func NewCommand(cli labcli.CLI) *cobra.Command {
@@ -50,9 +59,17 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 		Args:              cobra.MinimumNArgs(1),
 		ValidArgsFunction: completion.ActivePlays(cli),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.playID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.playID = playID
 			opts.command = cmd.Flags().Args()[1:]
 
+			opts.projectDefaults = func(play *api.Play) {
+				applyProjectDefaults(cmd, cli.Config().Project, play, &opts)
+			}
+
 			return labcli.WrapStatusError(runSSHSession(cmd.Context(), cli, &opts))
 		},
 	}
@@ -79,16 +96,30 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 		false,
 		`INSECURE: Forward the SSH agent to the playground VM (use at your own risk)`,
 	)
+	keepalive.AddFlag(flags, &opts.keepalive)
 
 	return cmd
 }
 
+func applyProjectDefaults(cmd *cobra.Command, project config.ProjectConfig, play *api.Play, opts *options) {
+	if !labcli.IsProjectPlay(project, play) {
+		return
+	}
+
+	labcli.ProjectDefault(cmd, "machine", &opts.machine, project.Machine)
+	labcli.ProjectDefault(cmd, "user", &opts.user, project.User)
+}
+
 func runSSHSession(ctx context.Context, cli labcli.CLI, opts *options) error {
 	p, err := cli.Client().GetPlay(ctx, opts.playID)
 	if err != nil {
 		return fmt.Errorf("couldn't get playground: %w", err)
 	}
 
+	if opts.projectDefaults != nil {
+		opts.projectDefaults(p)
+	}
+
 	if opts.machine, err = p.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
@@ -96,6 +127,10 @@ func runSSHSession(ctx context.Context, cli labcli.CLI, opts *options) error {
 		return err
 	}
 
+	if opts.keepalive {
+		defer keepalive.StartSession(ctx, cli, p.ID)()
+	}
+
 	sess, errCh, err := StartSSHSession(ctx, cli, p, opts.machine, opts.user, opts.command, opts.forwardAgent)
 	if err != nil {
 		return fmt.Errorf("couldn't start SSH session: %w", err)
@@ -121,6 +156,64 @@ func StartSSHSession(
 	command []string,
 	forwardAgent bool,
 ) (*ssh.Session, <-chan error, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	sess, conn, err := connect(ctx, cli, play, machine, user, forwardAgent)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	runErrCh := make(chan error, 1)
+
+	go func() {
+		defer conn.Close()
+		defer cancel()
+		defer close(runErrCh)
+
+		err := sess.Run(ctx, cli, strings.Join(command, " "))
+		if err != nil {
+			runErrCh <- err
+		}
+	}()
+
+	return sess, runErrCh, nil
+}
+
+// RunSSHCommand runs a command on the machine without attaching it to the
+// terminal (no stdin, no PTY) and returns its combined output. A non-zero
+// exit status is an error. The connection is closed before it returns.
+func RunSSHCommand(
+	ctx context.Context,
+	cli labcli.CLI,
+	play *api.Play,
+	machine string,
+	user string,
+	command string,
+) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sess, conn, err := connect(ctx, cli, play, machine, user, false)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer sess.Close()
+
+	return sess.Exec(ctx, command)
+}
+
+// connect tunnels to the machine's SSH port and establishes an SSH client
+// connection. The tunnel lives as long as the context.
+func connect(
+	ctx context.Context,
+	cli labcli.CLI,
+	play *api.Play,
+	machine string,
+	user string,
+	forwardAgent bool,
+) (*ssh.Session, net.Conn, error) {
 	tunnel, err := portforward.StartTunnel(ctx, cli.Client(), portforward.TunnelOptions{
 		PlayID:          play.ID,
 		Machine:         machine,
@@ -132,8 +225,6 @@ func StartSSHSession(
 		return nil, nil, fmt.Errorf("couldn't start tunnel: %w", err)
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-
 	// Bind the local side of the forwarding synchronously, letting the kernel
 	// pick a guaranteed-free port (a fixed random port used to collide with
 	// the ephemeral port range, leaving the forwarder dead and every dial
@@ -144,13 +235,11 @@ func StartSSHSession(
 		RemotePort: "22",
 	})
 	if err != nil {
-		cancel()
 		return nil, nil, fmt.Errorf("couldn't start local port forwarding: %w", err)
 	}
 
 	_, localPort, err := net.SplitHostPort(localAddr.String())
 	if err != nil {
-		cancel()
 		return nil, nil, fmt.Errorf("couldn't parse forwarder's local address %q: %w", localAddr, err)
 	}
 
@@ -193,24 +282,10 @@ func StartSSHSession(
 
 		return nil
 	}, 60, 1*time.Second); err != nil {
-		cancel()
 		return nil, nil, err
 	}
 
-	runErrCh := make(chan error, 1)
-
-	go func() {
-		defer conn.Close()
-		defer cancel()
-		defer close(runErrCh)
-
-		err := sess.Run(ctx, cli, strings.Join(command, " "))
-		if err != nil {
-			runErrCh <- err
-		}
-	}()
-
-	return sess, runErrCh, nil
+	return sess, conn, nil
 }
 
 // isTransientSSHError tells apart transport failures that are likely to go
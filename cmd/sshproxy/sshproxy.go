@@ -13,6 +13,7 @@ import (
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/ide"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 	"github.com/iximiuz/labctl/internal/portforward"
 )
 
@@ -39,7 +40,11 @@ func NewCommand(cli labcli.CLI) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.SetQuiet(opts.Quiet)
 
-			opts.PlayID = args[0]
+			playID, err := playref.Resolve(cmd.Context(), cli, args[0])
+			if err != nil {
+				return labcli.WrapStatusError(err)
+			}
+			opts.PlayID = playID
 
 			if cmd.Flags().Changed("ide") && opts.IDE == "" {
 				opts.IDE = ide.VSCode
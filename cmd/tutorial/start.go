@@ -1,6 +1,7 @@
 package tutorial
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
@@ -17,6 +18,7 @@ import (
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/ide"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
 	"github.com/iximiuz/labctl/internal/safety"
 	issh "github.com/iximiuz/labctl/internal/ssh"
 )
@@ -168,6 +170,8 @@ func runStartTutorial(ctx context.Context, cli labcli.CLI, opts *startOptions) e
 		return fmt.Errorf("tutorial doesn't have a playground associated with it")
 	}
 
+	ledger.Record(cli, ledger.ActionStart, tut.Play)
+
 	if opts.machine, err = tut.Play.ResolveMachine(opts.machine); err != nil {
 		return err
 	}
@@ -270,9 +274,10 @@ func runStartTutorial(ctx context.Context, cli labcli.CLI, opts *startOptions) e
 				} else {
 					cli.PrintAux("Stopping the playground...\n")
 
-					if _, err := cli.Client().StopTutorial(ctx, tut.Name); err != nil {
+					if stopped, err := cli.Client().StopTutorial(ctx, tut.Name); err != nil {
 						cli.PrintErr("Error stopping the tutorial: %v\n", err)
 					} else {
+						ledger.Record(cli, ledger.ActionStop, cmp.Or(stopped.Play, tut.Play))
 						cli.PrintAux("Playground stopped.\r\n")
 					}
 				}
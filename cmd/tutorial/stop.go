@@ -1,6 +1,7 @@
 package tutorial
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/iximiuz/labctl/internal/completion"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
 )
 
 type stopOptions struct {
@@ -64,10 +66,13 @@ func runStopTutorial(ctx context.Context, cli labcli.CLI, opts *stopOptions) err
 		return nil
 	}
 
-	if _, err = cli.Client().StopTutorial(ctx, opts.tutorial); err != nil {
+	stopped, err := cli.Client().StopTutorial(ctx, opts.tutorial)
+	if err != nil {
 		return fmt.Errorf("couldn't stop the tutorial: %w", err)
 	}
 
+	ledger.Record(cli, ledger.ActionStop, cmp.Or(stopped.Play, tut.Play))
+
 	cli.PrintAux("Tutorial session has been stopped.\n")
 	return nil
 }
@@ -0,0 +1,461 @@
+package up
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/cmd/cp"
+	"github.com/iximiuz/labctl/cmd/ssh"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/labfile"
+	"github.com/iximiuz/labctl/internal/ledger"
+	"github.com/iximiuz/labctl/internal/portforward"
+	"github.com/iximiuz/labctl/internal/safety"
+)
+
+const upTimeout = 10 * time.Minute
+
+const example = `  # Bring up the environment described in ./labfile.yaml
+  labctl up
+
+  # Use a different labfile and don't block on port forwarding
+  labctl up -f envs/k8s.yaml --detach
+
+  # Replace the play after the playground definition has changed
+  labctl up --recreate
+`
+
+type options struct {
+	file string
+
+	detach   bool
+	recreate bool
+
+	safetyDisclaimerConsent bool
+
+	quiet bool
+}
+
+func NewCommand(cli labcli.CLI) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "up [flags]",
+		Short: `Bring up a playground environment described in a labfile`,
+		Long: `Bring up a playground environment described in a labfile: start (or reuse) a play,
+copy files to it, run the setup commands, and forward and expose ports.
+
+Running 'labctl up' again only does what hasn't been done yet - the play ID and
+the completed steps are recorded in .labctl/state.json next to the labfile.`,
+		Example: example,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.SetQuiet(opts.quiet)
+
+			return labcli.WrapStatusError(runUp(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&opts.file,
+		"file",
+		"f",
+		labfile.DefaultFileName,
+		`Path to the labfile`,
+	)
+	flags.BoolVarP(
+		&opts.detach,
+		"detach",
+		"d",
+		false,
+		`Don't forward the labfile's ports in the foreground (they stay saved in the play)`,
+	)
+	flags.BoolVar(
+		&opts.recreate,
+		"recreate",
+		false,
+		`Destroy the current play if the playground definition has changed and start a new one`,
+	)
+	flags.BoolVar(
+		&opts.safetyDisclaimerConsent,
+		"safety-disclaimer-consent",
+		false,
+		`Acknowledge the safety disclaimer`,
+	)
+	flags.BoolVarP(
+		&opts.quiet,
+		"quiet",
+		"q",
+		false,
+		`Only print playground's ID`,
+	)
+
+	return cmd
+}
+
+func runUp(ctx context.Context, cli labcli.CLI, opts *options) error {
+	lf, err := labfile.Load(opts.file)
+	if err != nil {
+		return err
+	}
+
+	state, err := labfile.LoadState(labfile.StatePath(lf))
+	if err != nil {
+		return err
+	}
+
+	play, err := ensurePlay(ctx, cli, lf, state, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := waitPlay(ctx, cli, play); err != nil {
+		return err
+	}
+
+	for _, f := range lf.Files {
+		machine, user, err := resolveMachineUser(play, lf, f.Machine, f.User)
+		if err != nil {
+			return fmt.Errorf("files: %w", err)
+		}
+
+		cli.PrintAux("Copying %s to %s:%s...\n", f.Source, machine, f.Target)
+		if err := cp.CopyToPlay(ctx, cli, play.ID, machine, user, lf.LocalPath(f.Source), f.Target); err != nil {
+			return fmt.Errorf("couldn't copy %s: %w", f.Source, err)
+		}
+	}
+
+	for _, step := range lf.Setup {
+		if state.IsSetupDone(step) {
+			continue
+		}
+
+		if err := runSetupStep(ctx, cli, play, lf, step); err != nil {
+			return err
+		}
+
+		state.SetupDone = append(state.SetupDone, step.ID())
+		if err := state.Save(); err != nil {
+			return err
+		}
+	}
+
+	if err := savePortForwards(ctx, cli, play, lf, state); err != nil {
+		return err
+	}
+
+	if err := exposePorts(ctx, cli, play, lf, state); err != nil {
+		return err
+	}
+
+	cli.PrintAux("Environment is up. Tear it down with 'labctl down'.\n")
+	cli.PrintOut("%s\n", play.ID)
+
+	if opts.detach || len(lf.PortForwards) == 0 {
+		return nil
+	}
+
+	resultCh, err := portforward.RestoreSavedForwards(ctx, cli.Client(), play.ID, cli)
+	if err != nil {
+		return err
+	}
+
+	return <-resultCh
+}
+
+// ensurePlay reuses the play from the state file if it's still there and was
+// started from the same playground definition. Otherwise, a new play is
+// started.
+func ensurePlay(
+	ctx context.Context,
+	cli labcli.CLI,
+	lf *labfile.Labfile,
+	state *labfile.State,
+	opts *options,
+) (*api.Play, error) {
+	if state.PlayID != "" {
+		play, err := cli.Client().GetPlay(ctx, state.PlayID)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			return nil, fmt.Errorf("couldn't get the playground %s: %w", state.PlayID, err)
+		}
+
+		switch {
+		case err != nil || play.StateIs(api.StateDestroyed):
+			cli.PrintAux("Playground %s is gone, starting a new one...\n", state.PlayID)
+
+		case state.PlaygroundDigest != lf.PlaygroundDigest():
+			if !opts.recreate {
+				return nil, labcli.NewStatusError(1,
+					"the playground definition has changed since %s was started - run 'labctl down' first or use --recreate",
+					state.PlayID)
+			}
+
+			cli.PrintAux("Playground definition has changed, destroying %s...\n", state.PlayID)
+			if err := cli.Client().DestroyPlay(ctx, state.PlayID); err != nil && !errors.Is(err, api.ErrNotFound) {
+				return nil, fmt.Errorf("couldn't destroy the playground %s: %w", state.PlayID, err)
+			}
+			ledger.Record(cli, ledger.ActionDestroy, play)
+
+		case play.StateIs(api.StateStopped):
+			cli.PrintAux("Restarting playground %s...\n", play.ID)
+			if play, err = cli.Client().RestartPlay(ctx, play.ID); err != nil {
+				return nil, fmt.Errorf("couldn't restart the playground: %w", err)
+			}
+			ledger.Record(cli, ledger.ActionRestart, play)
+			return play, nil
+
+		default:
+			cli.PrintAux("Reusing playground %s\n", play.ID)
+			return play, nil
+		}
+
+		state.Reset()
+	}
+
+	consent, err := safety.ShowPlaygroundDisclaimerIfNeeded(ctx, lf.Playground.Name, cli, opts.safetyDisclaimerConsent)
+	if err != nil {
+		return nil, err
+	}
+
+	req := api.CreatePlayRequest{
+		Playground:              lf.Playground.Name,
+		SafetyDisclaimerConsent: consent,
+	}
+	if lf.Playground.HasSpec() {
+		req.Tabs = lf.Playground.Tabs
+		req.Networks = lf.Playground.Networks
+		req.Machines = lf.Playground.Machines
+		req.InitTasks = lf.Playground.InitTasks
+	}
+
+	play, err := cli.Client().CreatePlay(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't start the playground: %w", err)
+	}
+
+	ledger.Record(cli, ledger.ActionStart, play)
+
+	cli.PrintAux("New %s playground started with ID %s\n", lf.Playground.Name, play.ID)
+
+	state.PlayID = play.ID
+	state.Playground = lf.Playground.Name
+	state.PlaygroundDigest = lf.PlaygroundDigest()
+	if err := state.Save(); err != nil {
+		return nil, err
+	}
+
+	if lf.Title != "" {
+		if _, err := cli.Client().SetPlayTitle(ctx, play.ID, lf.Title); err != nil {
+			cli.PrintErr("Warning: couldn't set the playground title: %v\n", err)
+		}
+	}
+
+	return play, nil
+}
+
+func waitPlay(ctx context.Context, cli labcli.CLI, play *api.Play) error {
+	if play.AllMachinesReady() && play.IsInitialized() {
+		return nil
+	}
+
+	playConn := api.NewPlayConn(ctx, play, cli.Client(), cli.Config().WebSocketOrigin())
+	if err := playConn.Start(); err != nil {
+		return fmt.Errorf("couldn't start play connection: %w", err)
+	}
+	defer playConn.Close()
+
+	spin := spinner.New(spinner.CharSets[38], 300*time.Millisecond)
+	spin.Writer = cli.AuxStream()
+	if err := playConn.WaitMachinesRunning(upTimeout, spin); err != nil {
+		return fmt.Errorf("couldn't wait for the playground machines to start: %w", err)
+	}
+
+	spin = spinner.New(spinner.CharSets[38], 300*time.Millisecond)
+	spin.Writer = cli.AuxStream()
+	if err := playConn.WaitMachinesReady(upTimeout, spin); err != nil {
+		return fmt.Errorf("couldn't wait for the playground machines to become ready: %w", err)
+	}
+
+	if len(play.Tasks) > 0 && !play.IsInitialized() {
+		spin = spinner.New(spinner.CharSets[38], 300*time.Millisecond)
+		spin.Writer = cli.AuxStream()
+		if err := playConn.WaitPlayReady(upTimeout, spin); err != nil {
+			return fmt.Errorf("playground initialization failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func runSetupStep(
+	ctx context.Context,
+	cli labcli.CLI,
+	play *api.Play,
+	lf *labfile.Labfile,
+	step labfile.SetupStep,
+) error {
+	machine, user, err := resolveMachineUser(play, lf, step.Machine, step.User)
+	if err != nil {
+		return fmt.Errorf("setup: %w", err)
+	}
+
+	cli.PrintAux("Running on %s: %s\n", machine, step.Run)
+
+	sess, errCh, err := ssh.StartSSHSession(ctx, cli, play, machine, user, []string{step.Run}, false)
+	if err != nil {
+		return fmt.Errorf("couldn't start SSH session: %w", err)
+	}
+
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("setup command %q failed: %w", step.Run, err)
+	}
+
+	if err := sess.Wait(); err != nil {
+		slog.Debug("SSH session wait said: " + err.Error())
+	}
+
+	return nil
+}
+
+// savePortForwards adds the labfile's port forwards to the play, so that
+// 'labctl port-forward --restore' picks them up too.
+func savePortForwards(
+	ctx context.Context,
+	cli labcli.CLI,
+	play *api.Play,
+	lf *labfile.Labfile,
+	state *labfile.State,
+) error {
+	for _, fwd := range lf.PortForwards {
+		machine, err := play.ResolveMachine(firstNonEmpty(fwd.Machine, lf.Machine))
+		if err != nil {
+			return fmt.Errorf("portForwards: %w", err)
+		}
+
+		saved := labfile.ForwardedPort{Kind: "local", Machine: machine, Spec: fwd.Local}
+		spec, err := portforward.ParseLocal(fwd.Local)
+		if fwd.Remote != "" {
+			saved = labfile.ForwardedPort{Kind: "remote", Machine: machine, Spec: fwd.Remote}
+			spec, err = portforward.ParseRemote(fwd.Remote)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s port forwarding spec %q: %w", saved.Kind, saved.Spec, err)
+		}
+
+		if state.HasPortForward(saved) {
+			continue
+		}
+
+		pf, err := spec.ToPortForward(machine)
+		if err != nil {
+			return err
+		}
+		if err := cli.Client().AddPortForward(ctx, play.ID, *pf); err != nil {
+			return fmt.Errorf("couldn't save port forward %s: %w", saved.Spec, err)
+		}
+
+		state.PortForwards = append(state.PortForwards, saved)
+		if err := state.Save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exposePorts(
+	ctx context.Context,
+	cli labcli.CLI,
+	play *api.Play,
+	lf *labfile.Labfile,
+	state *labfile.State,
+) error {
+	if len(lf.Expose) == 0 {
+		return nil
+	}
+
+	existing, err := cli.Client().ListPorts(ctx, play.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't list exposed ports: %w", err)
+	}
+
+	for _, exp := range lf.Expose {
+		machine, err := play.ResolveMachine(firstNonEmpty(exp.Machine, lf.Machine))
+		if err != nil {
+			return fmt.Errorf("expose: %w", err)
+		}
+
+		var port *api.Port
+		for _, p := range existing {
+			if p.Machine == machine && p.Number == exp.Port {
+				port = p
+				break
+			}
+		}
+
+		if port == nil {
+			access := api.AccessPrivate
+			if exp.Public {
+				access = api.AccessPublic
+			}
+
+			port, err = cli.Client().ExposePort(ctx, play.ID, api.ExposePortRequest{
+				Machine:     machine,
+				Number:      exp.Port,
+				Access:      access,
+				TLS:         exp.TLS,
+				HostRewrite: exp.HostRewrite,
+				PathRewrite: exp.PathRewrite,
+			})
+			if err != nil {
+				return fmt.Errorf("couldn't expose port %d: %w", exp.Port, err)
+			}
+		}
+
+		cli.PrintAux("Port %s:%d is exposed at %s\n", machine, exp.Port, port.URL)
+
+		state.SetExposedPort(labfile.ExposedPortState{
+			ID:      port.ID,
+			Machine: machine,
+			Port:    port.Number,
+			URL:     port.URL,
+		})
+		if err := state.Save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resolveMachineUser(play *api.Play, lf *labfile.Labfile, machine, user string) (string, string, error) {
+	machine, err := play.ResolveMachine(firstNonEmpty(machine, lf.Machine))
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err = play.ResolveUser(machine, firstNonEmpty(user, lf.User))
+	if err != nil {
+		return "", "", err
+	}
+
+	return machine, user, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,232 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/ledger"
+)
+
+const example = `  # Hours per playground over the last 30 days
+  labctl usage
+
+  # Hours per project directory this week, as CSV
+  labctl usage --by project --since 7d -o csv
+
+  # Hours per day since the beginning of the month
+  labctl usage --by day --since 2026-10-01
+`
+
+const syncConcurrency = 8
+
+type options struct {
+	by      string
+	since   string
+	output  string
+	offline bool
+}
+
+func (opts *options) validate() error {
+	switch ledger.GroupBy(opts.by) {
+	case ledger.ByPlayground, ledger.ByDay, ledger.ByProject:
+	default:
+		return fmt.Errorf("invalid grouping: %s (supported groupings: playground, day, project)", opts.by)
+	}
+
+	switch opts.output {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("invalid output format: %s (supported formats: table, csv, json)", opts.output)
+	}
+
+	if _, err := parseSince(opts.since, time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewCommand(cli labcli.CLI) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "usage [flags]",
+		Short: `Summarize the playground time recorded by labctl (per playground, day, or project)`,
+		Long: `Summarize the playground time recorded by labctl (per playground, day, or project).
+
+labctl keeps a local ledger of the playgrounds it starts, stops, and destroys, along with
+the directory it was run in (or the one with the .labctl.yaml file). Playgrounds labctl
+has never started, stopped, or destroyed (e.g., the ones used only in the browser) aren't counted.`,
+		Example: example,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return labcli.WrapStatusError(runUsage(cmd.Context(), cli, &opts))
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&opts.by,
+		"by",
+		string(ledger.ByPlayground),
+		`Group the running time by: playground, day, project`,
+	)
+	flags.StringVar(
+		&opts.since,
+		"since",
+		"30d",
+		`Only count the time since the given date (YYYY-MM-DD) or that long ago (e.g., 7d, 12h)`,
+	)
+	flags.StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"table",
+		`Output format: table, csv, json`,
+	)
+	flags.BoolVar(
+		&opts.offline,
+		"offline",
+		false,
+		`Don't refresh the state of the recorded playgrounds from the server`,
+	)
+
+	return cmd
+}
+
+func runUsage(ctx context.Context, cli labcli.CLI, opts *options) error {
+	path := ledger.Path(cli.Config().PlaysDir)
+
+	entries, err := ledger.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if !opts.offline {
+		synced, err := syncPlays(ctx, cli, ledger.Fold(entries))
+		if err != nil {
+			return err
+		}
+
+		if len(synced) > 0 {
+			if err := ledger.Append(path, synced...); err != nil {
+				slog.Debug("Couldn't save the refreshed playground states", "error", err.Error())
+			}
+			entries = append(entries, synced...)
+		}
+	}
+
+	now := time.Now()
+	since, _ := parseSince(opts.since, now)
+
+	summaries := ledger.Summarize(ledger.Fold(entries), ledger.GroupBy(opts.by), since, now)
+
+	if len(summaries) == 0 && opts.output == "table" {
+		cli.PrintAux("No playground time recorded since %s.\n", since.Format(time.DateOnly))
+		return nil
+	}
+
+	printer := newPrinter(cli.OutputStream(), opts.output, strings.ToUpper(opts.by))
+	defer printer.Flush()
+
+	return printer.Print(summaries)
+}
+
+// syncPlays fetches the latest state events of the plays that may have
+// changed since they were recorded (e.g., expired or stopped in the browser).
+func syncPlays(ctx context.Context, cli labcli.CLI, usages []*ledger.PlayUsage) ([]ledger.Entry, error) {
+	var (
+		mu     sync.Mutex
+		synced []ledger.Entry
+	)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(syncConcurrency)
+
+	for _, u := range usages {
+		if u.Settled() {
+			continue
+		}
+
+		g.Go(func() error {
+			play, err := cli.Client().GetPlay(ctx, u.PlayID)
+			if errors.Is(err, api.ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				slog.Debug("Couldn't refresh the playground state", "play", u.PlayID, "error", err.Error())
+				return nil
+			}
+
+			if play.Status == nil || slices.Equal(play.Status.StateEvents, u.StateEvents) {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			synced = append(synced, ledger.NewEntry(ledger.ActionSync, play, u.Project))
+			return nil
+		})
+	}
+
+	return synced, g.Wait()
+}
+
+func newPrinter(w io.Writer, output string, key string) labcli.Printer[ledger.Summary, []ledger.Summary] {
+	switch output {
+	case "table":
+		return labcli.NewSliceTablePrinter(w, []string{key, "PLAYS", "HOURS"}, func(s ledger.Summary) []string {
+			return []string{s.Key, strconv.Itoa(s.Plays), strconv.FormatFloat(s.Hours, 'f', 1, 64)}
+		})
+	case "csv":
+		return labcli.NewCSVPrinter(w, []string{strings.ToLower(key), "plays", "hours"}, func(s ledger.Summary) []string {
+			return []string{s.Key, strconv.Itoa(s.Plays), strconv.FormatFloat(s.Hours, 'f', 2, 64)}
+		})
+	case "json":
+		return labcli.NewJSONPrinter[ledger.Summary, []ledger.Summary](w)
+	default:
+		// This should never happen
+		panic(fmt.Errorf("invalid output format: %s (supported formats: table, csv, json)", output))
+	}
+}
+
+// parseSince accepts a date (in the local time zone) or a duration, in
+// which case days are allowed too (e.g., 7d).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since value: %s (expected a date like 2026-01-31 or a duration like 7d or 12h)", s)
+	}
+	return now.Add(-d), nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/iximiuz/labctl/api"
 	"github.com/iximiuz/labctl/content"
 	"github.com/iximiuz/labctl/internal/labcli"
+	"github.com/iximiuz/labctl/internal/playref"
 )
 
 // CompletionFunc is the function signature for cobra ValidArgsFunction.
@@ -101,11 +102,18 @@ func plays(cli labcli.CLI, filter func(*api.Play) bool) CompletionFunc {
 			return nil, noFileComp
 		}
 
+		// Aliases are completed along with the IDs they point to.
+		aliases, _ := playref.LoadAliases(playref.AliasesPath(cli.Config().PlaysDir))
+
 		var completions []string
 		for _, p := range plays {
 			if filter(p) {
 				desc := fmt.Sprintf("%s (%s)", p.Playground.Name, p.State())
 				completions = append(completions, fmt.Sprintf("%s\t%s", p.ID, desc))
+
+				for _, name := range aliases.Names(p.ID) {
+					completions = append(completions, fmt.Sprintf("%s\t%s", name, desc))
+				}
 			}
 		}
 
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ProjectFileName = ".labctl.yaml"
+
+// ProjectConfig holds per-repository defaults for the command flags, so that
+// 'labctl playground start', 'labctl ssh', 'labctl ide', and
+// 'labctl port-forward' work without repeating them. Flags set explicitly
+// always win.
+type ProjectConfig struct {
+	// FilePath is empty if no project file was found.
+	FilePath string `yaml:"-"`
+
+	// Context is the config context to use unless --context is given.
+	Context string `yaml:"context,omitempty"`
+
+	Playground string `yaml:"playground,omitempty"`
+
+	// Manifest is relative to the project file's directory.
+	Manifest string `yaml:"manifest,omitempty"`
+
+	Machine string `yaml:"machine,omitempty"`
+
+	User string `yaml:"user,omitempty"`
+
+	PortForwards ProjectPortForwards `yaml:"port_forwards,omitempty"`
+
+	IDE ProjectIDE `yaml:"ide,omitempty"`
+}
+
+// ProjectPortForwards use the -L and -R flag syntax of 'labctl port-forward'.
+type ProjectPortForwards struct {
+	Local []string `yaml:"local,omitempty"`
+
+	Remote []string `yaml:"remote,omitempty"`
+}
+
+type ProjectIDE struct {
+	WorkDir string `yaml:"workdir,omitempty"`
+
+	Repos []string `yaml:"repos,omitempty"`
+}
+
+// FindProject looks for the project file in dir and all its parents. If
+// there is none, an empty ProjectConfig is returned.
+func FindProject(dir string) (ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+
+		project, err := LoadProject(path)
+		if err == nil {
+			return project, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return ProjectConfig{}, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ProjectConfig{}, nil
+		}
+		dir = parent
+	}
+}
+
+func LoadProject(path string) (ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+
+	var project ProjectConfig
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return ProjectConfig{}, fmt.Errorf("unable to decode %s: %s", path, err)
+	}
+
+	project.FilePath = path
+
+	if project.Manifest != "" && !filepath.IsAbs(project.Manifest) {
+		project.Manifest = filepath.Join(filepath.Dir(path), project.Manifest)
+	}
+
+	return project, nil
+}
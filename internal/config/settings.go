@@ -0,0 +1,444 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/internal/credentials"
+)
+
+// Setting is a single config value addressable by its key, e.g., from
+// 'labctl config get/set/unset'. The session and endpoint settings apply to
+// the active context, the transport ones - to all contexts.
+type Setting struct {
+	Key string
+
+	Description string
+
+	get func(c *Config) string
+
+	// set receives an already validated value.
+	set func(c *Config, value string)
+
+	// reset restores the value a fresh config would have.
+	reset func(c *Config)
+
+	validate func(value string) (string, error)
+
+	mask func(value string) string
+}
+
+var settings = []Setting{
+	{
+		Key:         "base_url",
+		Description: "iximiuz Labs endpoint URL (changing it signs out of the current session)",
+		get:         func(c *Config) string { return c.BaseURL },
+		set: func(c *Config, v string) {
+			// A session is bound to its endpoint.
+			if v != c.BaseURL {
+				c.SessionID = ""
+				c.AccessToken = ""
+			}
+			c.BaseURL = v
+			c.APIBaseURL = v + "/api"
+		},
+		reset: func(c *Config) {
+			if c.BaseURL != defaultBaseURL {
+				c.SessionID = ""
+				c.AccessToken = ""
+			}
+			c.BaseURL = defaultBaseURL
+			c.APIBaseURL = defaultAPIBaseURL
+		},
+		validate: validateHTTPURL,
+	},
+	{
+		Key:         "api_base_url",
+		Description: "iximiuz Labs API URL (derived from base_url unless set explicitly)",
+		get:         func(c *Config) string { return c.APIBaseURL },
+		set:         func(c *Config, v string) { c.APIBaseURL = v },
+		reset:       func(c *Config) { c.APIBaseURL = c.BaseURL + "/api" },
+		validate:    validateHTTPURL,
+	},
+	{
+		Key:         "session_id",
+		Description: "Session ID (set by 'labctl auth login')",
+		get:         func(c *Config) string { return c.SessionID },
+		set:         func(c *Config, v string) { c.SessionID = v },
+		reset:       func(c *Config) { c.SessionID = "" },
+		validate:    validateNonEmpty,
+		mask:        maskSecret,
+	},
+	{
+		Key:         "access_token",
+		Description: "Access token (set by 'labctl auth login')",
+		get:         func(c *Config) string { return c.AccessToken },
+		set:         func(c *Config, v string) { c.AccessToken = v },
+		reset:       func(c *Config) { c.AccessToken = "" },
+		validate:    validateNonEmpty,
+		mask:        maskSecret,
+	},
+	{
+		Key:         "credentials_helper",
+		Description: `Where to keep sessions instead of this file: "file" (passphrase-encrypted) or a docker-credential-<name> helper`,
+		get:         func(c *Config) string { return c.CredentialsHelper },
+		set: func(c *Config, v string) {
+			// The sessions get moved to the new store on the next dump.
+			c.CredentialsHelper = v
+			c.credentials = nil
+			c.inStore = false
+		},
+		reset: func(c *Config) {
+			c.CredentialsHelper = ""
+			c.credentials = nil
+			c.inStore = false
+		},
+		validate: validateCredentialsHelper,
+	},
+	{
+		Key:         "plays_dir",
+		Description: "Directory to keep local playground data in",
+		get:         func(c *Config) string { return c.PlaysDir },
+		set:         func(c *Config, v string) { c.PlaysDir = v },
+		reset:       func(c *Config) { c.PlaysDir = c.defaultPlaysDir(c.ActiveContext()) },
+		validate:    validatePath,
+	},
+	{
+		Key:         "ssh_identity_file",
+		Description: "SSH identity (private key) file to access playgrounds with",
+		get:         func(c *Config) string { return c.SSHIdentityFile },
+		set:         func(c *Config, v string) { c.SSHIdentityFile = v },
+		reset:       func(c *Config) { c.SSHIdentityFile = c.defaultSSHIdentityFile(c.ActiveContext()) },
+		validate:    validateExistingFile,
+	},
+	{
+		Key:         "transport.ca_file",
+		Description: "PEM bundle of extra CA certificates to trust",
+		get:         func(c *Config) string { return c.Transport.CAFile },
+		set:         func(c *Config, v string) { c.Transport.CAFile = v },
+		reset:       func(c *Config) { c.Transport.CAFile = "" },
+		validate:    validateExistingFile,
+	},
+	{
+		Key:         "transport.proxy_url",
+		Description: "Proxy URL (overrides HTTPS_PROXY)",
+		get:         func(c *Config) string { return c.Transport.ProxyURL },
+		set:         func(c *Config, v string) { c.Transport.ProxyURL = v },
+		reset:       func(c *Config) { c.Transport.ProxyURL = "" },
+		validate:    validateProxyURL,
+		mask:        maskURLPassword,
+	},
+	{
+		Key:         "transport.client_cert_file",
+		Description: "Client TLS certificate file",
+		get:         func(c *Config) string { return c.Transport.ClientCertFile },
+		set:         func(c *Config, v string) { c.Transport.ClientCertFile = v },
+		reset:       func(c *Config) { c.Transport.ClientCertFile = "" },
+		validate:    validateExistingFile,
+	},
+	{
+		Key:         "transport.client_key_file",
+		Description: "Client TLS private key file",
+		get:         func(c *Config) string { return c.Transport.ClientKeyFile },
+		set:         func(c *Config, v string) { c.Transport.ClientKeyFile = v },
+		reset:       func(c *Config) { c.Transport.ClientKeyFile = "" },
+		validate:    validateExistingFile,
+	},
+	{
+		Key:         "transport.dial_timeout",
+		Description: "TCP connection timeout (e.g., 10s)",
+		get:         func(c *Config) string { return formatDuration(c.Transport.DialTimeout) },
+		set:         func(c *Config, v string) { c.Transport.DialTimeout, _ = time.ParseDuration(v) },
+		reset:       func(c *Config) { c.Transport.DialTimeout = 0 },
+		validate:    validateDuration,
+	},
+	{
+		Key:         "transport.tls_handshake_timeout",
+		Description: "TLS handshake timeout (e.g., 10s)",
+		get:         func(c *Config) string { return formatDuration(c.Transport.TLSHandshakeTimeout) },
+		set:         func(c *Config, v string) { c.Transport.TLSHandshakeTimeout, _ = time.ParseDuration(v) },
+		reset:       func(c *Config) { c.Transport.TLSHandshakeTimeout = 0 },
+		validate:    validateDuration,
+	},
+}
+
+// Settings lists all the settings in the order they are best shown in.
+func Settings() []Setting {
+	return slices.Clone(settings)
+}
+
+func SettingKeys() []string {
+	var keys []string
+	for _, s := range settings {
+		keys = append(keys, s.Key)
+	}
+	return keys
+}
+
+func LookupSetting(key string) (Setting, error) {
+	for _, s := range settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown setting %q (known settings: %s)", key, strings.Join(SettingKeys(), ", "))
+}
+
+// Masked returns the value fit for printing, i.e., with secrets hidden.
+func (s Setting) Masked(value string) string {
+	if s.mask == nil || value == "" {
+		return value
+	}
+	return s.mask(value)
+}
+
+func (c *Config) Get(key string) (string, error) {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return s.get(c), nil
+}
+
+// Set validates and applies the value. Relative paths are made absolute, so
+// the setting keeps working no matter where labctl is run from.
+func (c *Config) Set(key, value string) error {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	value, err = s.validate(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	s.set(c, value)
+	return nil
+}
+
+// Unset restores the default value of the setting.
+func (c *Config) Unset(key string) error {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	s.reset(c)
+	return nil
+}
+
+// Validate checks the config file in homeDir: it must be well-formed YAML
+// without unknown keys, and the settings of every context must be valid. All
+// problems found are returned, not just the first one. A missing file is
+// valid - the defaults are used then.
+func Validate(homeDir string) []error {
+	data, err := os.ReadFile(ConfigFilePath(homeDir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return []error{fmt.Errorf("unable to read config file: %s", err)}
+	}
+
+	var cfg Config
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return []error{fmt.Errorf("unable to decode config from YAML: %s", err)}
+	}
+
+	var errs []error
+
+	check := func(prefix, key, value string) {
+		if value == "" {
+			return
+		}
+
+		s, _ := LookupSetting(key)
+		if _, err := s.validate(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", prefix, key, err))
+		}
+	}
+
+	checkContext := func(prefix string, ctx Context) {
+		check(prefix, "base_url", ctx.BaseURL)
+		check(prefix, "api_base_url", ctx.APIBaseURL)
+
+		// labctl generates the identity on login, so it's only missing
+		// for real if there is a session to use it with.
+		if ctx.SessionID != "" && ctx.AccessToken != "" {
+			check(prefix, "ssh_identity_file", ctx.SSHIdentityFile)
+		}
+
+		if (ctx.SessionID == "") != (ctx.AccessToken == "") {
+			errs = append(errs, fmt.Errorf("%ssession_id and access_token must be set together", prefix))
+		}
+	}
+
+	checkContext("", cfg.current())
+
+	check("", "credentials_helper", cfg.CredentialsHelper)
+	check("", "transport.ca_file", cfg.Transport.CAFile)
+	check("", "transport.proxy_url", cfg.Transport.ProxyURL)
+	check("", "transport.client_cert_file", cfg.Transport.ClientCertFile)
+	check("", "transport.client_key_file", cfg.Transport.ClientKeyFile)
+
+	// The key may live in the cert file (a combined PEM), but not the other way around.
+	if cfg.Transport.ClientKeyFile != "" && cfg.Transport.ClientCertFile == "" {
+		errs = append(errs, fmt.Errorf("transport.client_key_file requires transport.client_cert_file"))
+	}
+	if cfg.Transport.DialTimeout < 0 {
+		errs = append(errs, fmt.Errorf("transport.dial_timeout: must not be negative"))
+	}
+	if cfg.Transport.TLSHandshakeTimeout < 0 {
+		errs = append(errs, fmt.Errorf("transport.tls_handshake_timeout: must not be negative"))
+	}
+
+	seen := map[string]bool{}
+	for _, ctx := range cfg.Contexts {
+		if ctx == nil {
+			continue
+		}
+
+		prefix := fmt.Sprintf("context %q: ", ctx.Name)
+
+		if err := validateContextName(ctx.Name); err != nil {
+			errs = append(errs, err)
+		} else if ctx.Name == DefaultContextName {
+			errs = append(errs, fmt.Errorf("%sname is reserved for the top-level settings", prefix))
+		} else if seen[ctx.Name] {
+			errs = append(errs, fmt.Errorf("%sdefined more than once", prefix))
+		}
+		seen[ctx.Name] = true
+
+		checkContext(prefix, *ctx)
+	}
+
+	if cfg.CurrentContext != "" && cfg.CurrentContext != DefaultContextName && !seen[cfg.CurrentContext] {
+		errs = append(errs, fmt.Errorf("current_context: context %q not found", cfg.CurrentContext))
+	}
+
+	return errs
+}
+
+func validateNonEmpty(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("must not be empty")
+	}
+	return value, nil
+}
+
+func validateHTTPURL(value string) (string, error) {
+	value = strings.TrimSuffix(value, "/")
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return "", fmt.Errorf("%q is not an http(s):// URL", value)
+	}
+
+	return value, nil
+}
+
+func validateProxyURL(value string) (string, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		// The error message would contain the password.
+		return "", fmt.Errorf("malformed URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" || u.Host == "" {
+		return "", fmt.Errorf("%q is not an http(s):// or socks5:// URL", maskURLPassword(value))
+	}
+
+	return value, nil
+}
+
+func validateCredentialsHelper(value string) (string, error) {
+	if value == credentials.FileHelper {
+		return value, nil
+	}
+
+	program := credentials.HelperProgram(value)
+	if _, err := exec.LookPath(program); err != nil {
+		return "", fmt.Errorf("credentials helper %s not found", program)
+	}
+
+	return value, nil
+}
+
+func validatePath(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("must not be empty")
+	}
+	return filepath.Abs(value)
+}
+
+func validateExistingFile(value string) (string, error) {
+	path, err := validatePath(value)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file %s doesn't exist", path)
+		}
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+
+	return path, nil
+}
+
+func validateDuration(value string) (string, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return "", err
+	}
+	if d < 0 {
+		return "", fmt.Errorf("must not be negative")
+	}
+	return d.String(), nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// maskSecret keeps a few trailing characters, so that different secrets can
+// still be told apart.
+func maskSecret(value string) string {
+	if len(value) <= 8 {
+		return "********"
+	}
+	return "********" + value[len(value)-4:]
+}
+
+func maskURLPassword(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+	return u.Redacted()
+}
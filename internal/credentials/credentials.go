@@ -0,0 +1,62 @@
+// Package credentials keeps labctl sessions out of the plaintext config file:
+// in an external credentials helper or in a passphrase-encrypted file.
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// FileHelper is the name of the built-in helper backed by an encrypted file.
+const FileHelper = "file"
+
+const fileName = "credentials.enc"
+
+// PassphraseEnvVar unlocks the encrypted file non-interactively.
+const PassphraseEnvVar = "IXIMIUZ_CREDENTIALS_PASSPHRASE"
+
+var ErrNotFound = errors.New("credentials not found")
+
+type Credentials struct {
+	SessionID string
+
+	AccessToken string
+}
+
+func (c Credentials) IsEmpty() bool {
+	return c.SessionID == "" && c.AccessToken == ""
+}
+
+// Store keeps credentials under a key (the endpoint URL, possibly made unique
+// per config context).
+type Store interface {
+	// Get returns ErrNotFound if there is nothing under the key.
+	Get(key string) (Credentials, error)
+
+	Store(key string, creds Credentials) error
+
+	// Erase is a no-op if there is nothing under the key.
+	Erase(key string) error
+}
+
+// PassphraseFunc asks the user for the passphrase of the encrypted file.
+type PassphraseFunc func() (string, error)
+
+// New returns the store for the credentials_helper setting: either the
+// built-in encrypted file (kept in dir) or an external helper.
+func New(helper string, dir string, prompt PassphraseFunc) Store {
+	if helper == FileHelper {
+		return NewFileStore(filepath.Join(dir, fileName), func() (string, error) {
+			if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+				return passphrase, nil
+			}
+			if prompt == nil {
+				return "", errors.New("no passphrase provided (set " + PassphraseEnvVar + ")")
+			}
+			return prompt()
+		})
+	}
+
+	return NewHelperStore(helper)
+}
@@ -0,0 +1,89 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	prompts := 0
+	store := NewFileStore(path, func() (string, error) {
+		prompts++
+		return "correct horse", nil
+	})
+
+	_, err := store.Get("https://labs.example.com")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	creds := Credentials{SessionID: "session", AccessToken: "token"}
+	require.NoError(t, store.Store("https://labs.example.com", creds))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "token")
+
+	got, err := NewFileStore(path, func() (string, error) { return "correct horse", nil }).Get("https://labs.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, creds, got)
+	assert.Equal(t, 1, prompts)
+
+	_, err = NewFileStore(path, func() (string, error) { return "wrong", nil }).Get("https://labs.example.com")
+	require.ErrorContains(t, err, "wrong passphrase")
+
+	require.NoError(t, store.Erase("https://labs.example.com"))
+	_, err = store.Get("https://labs.example.com")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+// A toy docker-credential-helpers implementation keeping one entry per file.
+const fakeHelper = `#!/bin/sh
+dir="$(dirname "$0")/store"
+mkdir -p "$dir"
+case "$1" in
+  store)
+    payload="$(cat)"
+    key="$(echo "$payload" | sed 's/.*"ServerURL":"\([^"]*\)".*/\1/' | tr '/:' '__')"
+    echo "$payload" > "$dir/$key" ;;
+  get)
+    key="$(cat | tr '/:' '__')"
+    [ -f "$dir/$key" ] || { echo "credentials not found in native keychain"; exit 1; }
+    cat "$dir/$key" ;;
+  erase)
+    key="$(cat | tr '/:' '__')"
+    [ -f "$dir/$key" ] || { echo "credentials not found in native keychain"; exit 1; }
+    rm "$dir/$key" ;;
+esac
+`
+
+func TestHelperStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake helper is a shell script")
+	}
+
+	helper := filepath.Join(t.TempDir(), "docker-credential-fake")
+	require.NoError(t, os.WriteFile(helper, []byte(fakeHelper), 0o755))
+
+	store := NewHelperStore(helper)
+
+	_, err := store.Get("https://labs.example.com")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	creds := Credentials{SessionID: "session", AccessToken: "token"}
+	require.NoError(t, store.Store("https://labs.example.com", creds))
+
+	got, err := store.Get("https://labs.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, creds, got)
+
+	require.NoError(t, store.Erase("https://labs.example.com"))
+	require.NoError(t, store.Erase("https://labs.example.com"))
+
+	assert.Equal(t, "docker-credential-osxkeychain", HelperProgram("osxkeychain"))
+}
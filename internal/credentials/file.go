@@ -0,0 +1,192 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const fileFormatVersion = 1
+
+// scrypt parameters recommended for interactive logins.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// encryptedFile is what's on disk. The salt and nonce are regenerated on
+// every write.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+type fileStore struct {
+	path       string
+	passphrase PassphraseFunc
+
+	// Asking for the passphrase once per run is enough.
+	cached string
+}
+
+var _ Store = (*fileStore)(nil)
+
+// NewFileStore keeps credentials in a file encrypted with AES-256-GCM, the
+// key being derived from a passphrase with scrypt.
+func NewFileStore(path string, passphrase PassphraseFunc) Store {
+	return &fileStore{path: path, passphrase: passphrase}
+}
+
+func (s *fileStore) Get(key string) (Credentials, error) {
+	entries, err := s.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	creds, ok := entries[key]
+	if !ok {
+		return Credentials{}, ErrNotFound
+	}
+	return creds, nil
+}
+
+func (s *fileStore) Store(key string, creds Credentials) error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	entries[key] = creds
+	return s.write(entries)
+}
+
+func (s *fileStore) Erase(key string) error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+
+	delete(entries, key)
+	return s.write(entries)
+}
+
+func (s *fileStore) read() (map[string]Credentials, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Credentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to decode credentials file %s: %w", s.path, err)
+	}
+	if file.Version != fileFormatVersion {
+		return nil, fmt.Errorf("unsupported credentials file version %d", file.Version)
+	}
+
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		s.cached = ""
+		return nil, fmt.Errorf("unable to decrypt credentials file %s: wrong passphrase?", s.path)
+	}
+
+	entries := map[string]Credentials{}
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, fmt.Errorf("unable to decode credentials: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *fileStore) write(entries map[string]Credentials) error {
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(encryptedFile{
+		Version: fileFormatVersion,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("unable to create credentials directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("unable to write credentials file: %w", err)
+	}
+
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("unable to rename credentials file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if s.cached == "" {
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get credentials file passphrase: %w", err)
+		}
+		if passphrase == "" {
+			return nil, errors.New("credentials file passphrase must not be empty")
+		}
+		s.cached = passphrase
+	}
+
+	key, err := scrypt.Key([]byte(s.cached), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
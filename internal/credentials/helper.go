@@ -0,0 +1,103 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Helpers report a missing entry on stdout with this message (and exit 1).
+const helperNotFoundMessage = "credentials not found in native keychain"
+
+// helperPayload is the docker-credential-helpers wire format.
+type helperPayload struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+type helperStore struct {
+	program string
+}
+
+var _ Store = (*helperStore)(nil)
+
+// NewHelperStore talks to an external credentials helper following the
+// docker-credential-helpers protocol, so the existing ones (osxkeychain,
+// secretservice, pass, wincred) work out of the box. A bare name refers to a
+// docker-credential-<name> executable in PATH, anything with a path
+// separator - to the executable itself.
+func NewHelperStore(helper string) Store {
+	return &helperStore{program: HelperProgram(helper)}
+}
+
+func HelperProgram(helper string) string {
+	if strings.ContainsAny(helper, `/\`) {
+		return helper
+	}
+	return "docker-credential-" + helper
+}
+
+func (s *helperStore) Get(key string) (Credentials, error) {
+	out, err := s.run("get", []byte(key))
+	if err != nil {
+		if strings.Contains(err.Error(), helperNotFoundMessage) {
+			return Credentials{}, ErrNotFound
+		}
+		return Credentials{}, err
+	}
+
+	var payload helperPayload
+	if err := json.Unmarshal(out, &payload); err != nil {
+		return Credentials{}, fmt.Errorf("%s get: malformed response: %w", s.program, err)
+	}
+
+	return Credentials{
+		SessionID:   payload.Username,
+		AccessToken: payload.Secret,
+	}, nil
+}
+
+func (s *helperStore) Store(key string, creds Credentials) error {
+	in, err := json.Marshal(helperPayload{
+		ServerURL: key,
+		Username:  creds.SessionID,
+		Secret:    creds.AccessToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.run("store", in)
+	return err
+}
+
+func (s *helperStore) Erase(key string) error {
+	_, err := s.run("erase", []byte(key))
+	if err != nil && strings.Contains(err.Error(), helperNotFoundMessage) {
+		return nil
+	}
+	return err
+}
+
+func (s *helperStore) run(action string, input []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(s.program, action)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Helpers print the reason to stdout, not stderr.
+		msg := strings.TrimSpace(stdout.String() + " " + stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s %s: %s", s.program, action, msg)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,308 @@
+// Package har records HTTP and WebSocket traffic into a HAR 1.2 file
+// (http://www.softwareishard.com/blog/har-12-spec/) that can be attached to a
+// bug report or opened in the browser's dev tools.
+//
+// Credentials never make it into the file: the Authorization, Cookie and
+// Set-Cookie headers, as well as token-bearing fields of JSON bodies, are
+// redacted before an entry is stored.
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	harVersion = "1.2"
+
+	// maxBodySize caps how much of a request or response body is recorded.
+	// Content downloads can be big, and the trace is about the exchange, not
+	// the payload.
+	maxBodySize = 1 << 20
+
+	// flushInterval is how often the new entries are written to disk.
+	flushInterval = time.Second
+)
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+
+	// Error is a non-standard field (custom fields start with an underscore)
+	// set when the exchange failed before a response was received.
+	Error string `json:"_error,omitempty"`
+
+	// ResourceType is the non-standard field Chrome uses to tell WebSocket
+	// handshakes apart.
+	ResourceType string `json:"_resourceType,omitempty"`
+}
+
+type Request struct {
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	HTTPVersion string    `json:"httpVersion"`
+	Cookies     []Cookie  `json:"cookies"`
+	Headers     []NameVal `json:"headers"`
+	QueryString []NameVal `json:"queryString"`
+	PostData    *PostData `json:"postData,omitempty"`
+	HeadersSize int       `json:"headersSize"`
+	BodySize    int       `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int       `json:"status"`
+	StatusText  string    `json:"statusText"`
+	HTTPVersion string    `json:"httpVersion"`
+	Cookies     []Cookie  `json:"cookies"`
+	Headers     []NameVal `json:"headers"`
+	Content     Content   `json:"content"`
+	RedirectURL string    `json:"redirectURL"`
+	HeadersSize int       `json:"headersSize"`
+	BodySize    int       `json:"bodySize"`
+}
+
+type NameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Recorder collects HAR entries and keeps the file on disk up to date. HAR
+// is a single JSON document, so the whole file is rewritten (atomically) -
+// in the background, every flushInterval, if there are new entries, and once
+// more on Close. labctl is often interrupted with Ctrl-C exactly when
+// something misbehaves, and that's when the trace is needed the most, so
+// waiting for a clean exit isn't an option.
+type Recorder struct {
+	path string
+
+	mu    sync.Mutex
+	log   Log
+	dirty bool
+
+	// secretURLs are the one-time URLs (e.g., the tunnels' login URLs) seen
+	// in the responses - they are credentials themselves.
+	secretURLs map[string]bool
+
+	closeOnce sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+func NewRecorder(path string, version string) (*Recorder, error) {
+	r := &Recorder{
+		path: path,
+		log: Log{
+			Version: harVersion,
+			Creator: Creator{Name: "labctl", Version: version},
+			Entries: []Entry{},
+		},
+		dirty:  true,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+
+	// Fail early on an unwritable path rather than after the first request.
+	if err := r.flush(); err != nil {
+		return nil, err
+	}
+
+	go r.flushPeriodically()
+
+	return r, nil
+}
+
+// Close stops the background flushing and writes the entries recorded since
+// the last flush.
+func (r *Recorder) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stopCh)
+		<-r.doneCh
+
+		err = r.flush()
+	})
+	return err
+}
+
+// Entries returns a copy of the entries recorded so far.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Entry(nil), r.log.Entries...)
+}
+
+func (r *Recorder) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.log.Entries = append(r.log.Entries, e)
+	r.dirty = true
+}
+
+func (r *Recorder) rememberSecretURLs(body []byte) {
+	urls := findSecretURLs(body)
+	if len(urls) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.secretURLs == nil {
+		r.secretURLs = map[string]bool{}
+	}
+	for _, u := range urls {
+		r.secretURLs[u] = true
+	}
+}
+
+func (r *Recorder) isSecretURL(u string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.secretURLs[u]
+}
+
+func (r *Recorder) flushPeriodically() {
+	defer close(r.doneCh)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+
+		case <-ticker.C:
+			// A failed trace write must not fail the command being traced.
+			_ = r.flush()
+		}
+	}
+}
+
+// flush writes the file if there are new entries. It's only called from one
+// goroutine at a time (NewRecorder, then the background flusher, then Close).
+func (r *Recorder) flush() error {
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+
+	// Entries are append-only, so the snapshot can be encoded without
+	// holding up the traffic being recorded.
+	log := r.log
+	log.Entries = log.Entries[:len(log.Entries):len(log.Entries)]
+	r.dirty = false
+	r.mu.Unlock()
+
+	if err := r.write(log); err != nil {
+		// Try again on the next tick.
+		r.mu.Lock()
+		r.dirty = true
+		r.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Recorder) write(log Log) error {
+	data, err := json.MarshalIndent(struct {
+		Log Log `json:"log"`
+	}{log}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode HAR: %w", err)
+	}
+
+	// Write to a temp file next to the target and rename it, so that the file
+	// is never seen (or left, if labctl is killed) half-written.
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create HAR file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write HAR file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write HAR file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("unable to rename HAR file: %w", err)
+	}
+
+	return nil
+}
+
+func toHeaders(h http.Header) []NameVal {
+	headers := []NameVal{}
+	for name, values := range h {
+		for _, v := range values {
+			headers = append(headers, NameVal{Name: name, Value: redactHeader(name, v)})
+		}
+	}
+	return headers
+}
+
+func toCookies(cookies []*http.Cookie) []Cookie {
+	result := []Cookie{}
+	for _, c := range cookies {
+		result = append(result, Cookie{Name: c.Name, Value: redacted})
+	}
+	return result
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
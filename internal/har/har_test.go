@@ -0,0 +1,214 @@
+package har
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/api/apitest"
+)
+
+func TestRecorderRedactsCredentials(t *testing.T) {
+	srv := apitest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	recorder, err := NewRecorder(path, "test")
+	require.NoError(t, err)
+
+	client := api.NewClient(api.ClientOptions{
+		BaseURL:     srv.URL,
+		APIBaseURL:  srv.URL + "/api",
+		SessionID:   apitest.DefaultSessionID,
+		AccessToken: apitest.DefaultAccessToken,
+		Tracer:      recorder,
+	})
+
+	ctx := context.Background()
+
+	session, err := client.CreateSession(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, session.AccessToken)
+
+	_, err = client.ListPlays(ctx, api.ListPlaysQueryParams{})
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// Nothing secret may reach the file, however it's encoded.
+	assert.NotContains(t, string(data), apitest.DefaultAccessToken)
+	assert.NotContains(t, string(data), session.AccessToken)
+
+	var har struct {
+		Log Log `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(data, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, "labctl", har.Log.Creator.Name)
+	require.Len(t, har.Log.Entries, 2)
+
+	create := har.Log.Entries[0]
+	assert.Equal(t, http.MethodPost, create.Request.Method)
+	assert.Equal(t, http.StatusOK, create.Response.Status)
+	assert.Contains(t, create.Response.Content.Text, `"accessToken":"[REDACTED]"`)
+
+	list := har.Log.Entries[1]
+	assert.Equal(t, srv.URL+"/api/plays", list.Request.URL)
+	assert.Contains(t, list.Request.Headers, NameVal{Name: "Authorization", Value: "Basic [REDACTED]"})
+}
+
+func TestRecorderWebSocketAndFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	recorder, err := NewRecorder(path, "test")
+	require.NoError(t, err)
+
+	recorder.TraceWebSocket("wss://example.com/conns/1", http.Header{
+		"Cookie": {".ixcondsess=secret; theme=dark"},
+	}, &http.Response{
+		StatusCode: http.StatusSwitchingProtocols,
+		Header:     http.Header{"Set-Cookie": {".ixcondsess=secret; Path=/; HttpOnly"}},
+	}, nil, time.Now())
+
+	httpClient := &http.Client{Transport: recorder.WrapTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, io.ErrUnexpectedEOF
+	}))}
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/api/x", strings.NewReader(`{"token":"secret"}`))
+	require.NoError(t, err)
+	_, err = httpClient.Do(req)
+	require.Error(t, err)
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 2)
+
+	ws := entries[0]
+	assert.Equal(t, "websocket", ws.ResourceType)
+	assert.Equal(t, http.StatusSwitchingProtocols, ws.Response.Status)
+	assert.Contains(t, ws.Request.Headers, NameVal{Name: "Cookie", Value: ".ixcondsess=[REDACTED]; theme=[REDACTED]"})
+	assert.Contains(t, ws.Response.Headers, NameVal{Name: "Set-Cookie", Value: ".ixcondsess=[REDACTED]; Path=/; HttpOnly"})
+	assert.Equal(t, []Cookie{{Name: ".ixcondsess", Value: redacted}}, ws.Response.Cookies)
+
+	failed := entries[1]
+	assert.Contains(t, failed.Error, io.ErrUnexpectedEOF.Error())
+	require.NotNil(t, failed.Request.PostData)
+	assert.JSONEq(t, `{"token":"[REDACTED]"}`, failed.Request.PostData.Text)
+
+	require.NoError(t, recorder.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+}
+
+func TestRecorderRedactsURLs(t *testing.T) {
+	const loginURL = "https://conductor.example.com/login/s3cr3t-path?token=s3cr3t-token"
+
+	recorder, err := NewRecorder(filepath.Join(t.TempDir(), "trace.har"), "test")
+	require.NoError(t, err)
+
+	httpClient := &http.Client{Transport: recorder.WrapTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := "{}"
+		if req.URL.Path == "/api/plays/1/tunnels" {
+			body = `{"url":"https://tunnel.example.com","loginUrl":"` + loginURL + `"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}))}
+
+	for _, u := range []string{
+		"http://example.com/api/plays/1/tunnels",
+		loginURL,
+		"http://example.com/api/x?page=2&access_token=abc&Token=def",
+	} {
+		resp, err := httpClient.Get(u)
+		require.NoError(t, err)
+		_, _ = io.ReadAll(resp.Body)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 3)
+
+	assert.Contains(t, entries[0].Response.Content.Text, `"loginUrl":"[REDACTED]"`)
+
+	assert.Equal(t, "https://conductor.example.com/[REDACTED]", entries[1].Request.URL)
+	assert.Empty(t, entries[1].Request.QueryString)
+
+	assert.Equal(t, "http://example.com/api/x?page=2&access_token=[REDACTED]&Token=[REDACTED]", entries[2].Request.URL)
+	assert.Equal(t, []NameVal{
+		{Name: "page", Value: "2"},
+		{Name: "access_token", Value: redacted},
+		{Name: "Token", Value: redacted},
+	}, entries[2].Request.QueryString)
+
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+	assert.NotContains(t, string(data), "abc")
+}
+
+func TestRecorderFlushesOnClose(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.har")
+
+	recorder, err := NewRecorder(path, "test")
+	require.NoError(t, err)
+
+	// The (empty) file is there right away...
+	assert.Len(t, readEntries(t, path), 0)
+
+	for i := 0; i < 3; i++ {
+		recorder.TraceWebSocket("wss://example.com/conns/1", http.Header{}, nil, io.EOF, time.Now())
+	}
+
+	// ...but the entries aren't written one by one.
+	assert.Len(t, readEntries(t, path), 0)
+
+	require.NoError(t, recorder.Close())
+	assert.Len(t, readEntries(t, path), 3)
+
+	// Closing twice is fine.
+	require.NoError(t, recorder.Close())
+
+	// No temp files are left behind.
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "trace.har", files[0].Name())
+}
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var har struct {
+		Log Log `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(data, &har))
+
+	return har.Log.Entries
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,160 @@
+package har
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretFields are the JSON body fields that carry credentials: the CLI
+// session's access token and the one-time login URLs the API hands out.
+var secretFields = map[string]bool{
+	"accessToken":  true,
+	"access_token": true,
+	"token":        true,
+	"loginUrl":     true,
+	"authUrl":      true,
+	"password":     true,
+}
+
+// secretURLFields are the secretFields holding one-time URLs. The URLs are
+// remembered, so that the requests made to them later are redacted too.
+var secretURLFields = []string{"loginUrl", "authUrl"}
+
+// secretParams are the query parameters that carry credentials.
+var secretParams = map[string]bool{
+	"token":        true,
+	"access_token": true,
+	"accesstoken":  true,
+	"password":     true,
+	"secret":       true,
+	"code":         true,
+	"signature":    true,
+	"sig":          true,
+}
+
+func redactHeader(name, value string) string {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Proxy-Authorization":
+		// Keep the scheme - "Basic" vs "Bearer" is useful when debugging.
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + redacted
+		}
+		return redacted
+
+	case "Cookie":
+		// Names stay, values go - including the conductor's .ixcondsess.
+		var parts []string
+		for _, part := range strings.Split(value, ";") {
+			name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+			parts = append(parts, name+"="+redacted)
+		}
+		return strings.Join(parts, "; ")
+
+	case "Set-Cookie":
+		pair, attrs, hasAttrs := strings.Cut(value, ";")
+		name, _, _ := strings.Cut(pair, "=")
+		if hasAttrs {
+			return name + "=" + redacted + ";" + attrs
+		}
+		return name + "=" + redacted
+	}
+
+	return value
+}
+
+// redactBody masks secretFields in JSON bodies. Anything that isn't JSON is
+// returned as is.
+func redactBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	if !redactValue(v) {
+		return string(body)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func redactValue(v any) (changed bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if secretFields[k] {
+				if s, ok := val.(string); ok && s != "" {
+					v[k] = redacted
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(val) || changed
+		}
+
+	case []any:
+		for _, val := range v {
+			changed = redactValue(val) || changed
+		}
+	}
+
+	return changed
+}
+
+// redactQuery masks the values of secretParams in a raw query string, keeping
+// the rest of it byte for byte.
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		name, _, hasValue := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if hasValue && secretParams[strings.ToLower(name)] {
+			parts[i] = part[:strings.Index(part, "=")+1] + redacted
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// findSecretURLs returns the values of secretURLFields found in a JSON body.
+func findSecretURLs(body []byte) []string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+
+	var urls []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, val := range v {
+				for _, field := range secretURLFields {
+					if s, ok := val.(string); ok && k == field && s != "" {
+						urls = append(urls, s)
+					}
+				}
+				walk(val)
+			}
+		case []any:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+	walk(v)
+
+	return urls
+}
@@ -0,0 +1,255 @@
+package har
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+var _ api.Tracer = (*Recorder)(nil)
+
+type transport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+// WrapTransport returns a RoundTripper that records every exchange going
+// through next. A nil next means http.DefaultTransport.
+func (r *Recorder) WrapTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{recorder: r, next: next}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+
+	// A RoundTripper must not modify the request, so a body that can't be
+	// re-read is recorded from (and sent as) a copy.
+	var reqBody []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(rc, maxBodySize))
+			rc.Close()
+		}
+	} else if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = data
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	entry := Entry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request:         t.recorder.newRequest(req, reqBody),
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	waited := time.Since(started)
+
+	if err != nil {
+		entry.Time = millis(waited)
+		entry.Timings = Timings{Wait: millis(waited)}
+		entry.Error = err.Error()
+		t.recorder.add(entry)
+
+		return nil, err
+	}
+
+	entry.Response = newResponse(resp)
+
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		done: func(body []byte, size int) {
+			total := time.Since(started)
+
+			entry.Time = millis(total)
+			entry.Timings = Timings{Wait: millis(waited), Receive: millis(total - waited)}
+			entry.Response.BodySize = size
+			entry.Response.Content.Size = size
+			setText(&entry.Response.Content, body, size)
+
+			t.recorder.rememberSecretURLs(body)
+			t.recorder.add(entry)
+		},
+	}
+
+	return resp, nil
+}
+
+// TraceWebSocket records a WebSocket handshake. Messages exchanged over the
+// connection aren't recorded - the handshake is what fails in practice.
+func (r *Recorder) TraceWebSocket(url string, header http.Header, resp *http.Response, err error, started time.Time) {
+	elapsed := time.Since(started)
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if req != nil {
+		req.Header = header.Clone()
+	}
+
+	entry := Entry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            millis(elapsed),
+		Timings:         Timings{Wait: millis(elapsed)},
+		ResourceType:    "websocket",
+	}
+
+	if req != nil {
+		entry.Request = r.newRequest(req, nil)
+	} else {
+		entry.Request = Request{Method: http.MethodGet, URL: url, Headers: toHeaders(header)}
+	}
+
+	if resp != nil {
+		entry.Response = newResponse(resp)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	r.add(entry)
+}
+
+func (r *Recorder) newRequest(req *http.Request, body []byte) Request {
+	// The query's credentials are masked, and a one-time URL seen in an
+	// earlier response keeps only its host.
+	u := *req.URL
+	u.RawQuery = redactQuery(u.RawQuery)
+
+	recordedURL := u.String()
+	if r.isSecretURL(req.URL.String()) {
+		u.RawQuery = ""
+		recordedURL = (&url.URL{Scheme: u.Scheme, Host: u.Host}).String() + "/" + redacted
+	}
+
+	request := Request{
+		Method:      req.Method,
+		URL:         recordedURL,
+		HTTPVersion: httpVersion(req.Proto),
+		Cookies:     toCookies(req.Cookies()),
+		Headers:     toHeaders(req.Header),
+		QueryString: []NameVal{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+
+	if u.RawQuery != "" {
+		for _, part := range strings.Split(u.RawQuery, "&") {
+			name, value, _ := strings.Cut(part, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if value != redacted {
+				if unescaped, err := url.QueryUnescape(value); err == nil {
+					value = unescaped
+				}
+			}
+			request.QueryString = append(request.QueryString, NameVal{Name: name, Value: value})
+		}
+	}
+
+	if len(body) > 0 {
+		var content Content
+		setText(&content, body, len(body))
+
+		request.PostData = &PostData{
+			MimeType: mimeType(req.Header),
+			Text:     content.Text,
+		}
+	}
+
+	return request
+}
+
+func newResponse(resp *http.Response) Response {
+	return Response{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: httpVersion(resp.Proto),
+		Cookies:     toCookies(resp.Cookies()),
+		Headers:     toHeaders(resp.Header),
+		Content:     Content{MimeType: mimeType(resp.Header)},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+}
+
+func setText(c *Content, body []byte, size int) {
+	switch {
+	case len(body) == 0:
+	case !utf8.Valid(body):
+		c.Comment = "binary content omitted"
+	default:
+		c.Text = redactBody(body)
+		if size > len(body) {
+			c.Comment = "content truncated"
+		}
+	}
+}
+
+func mimeType(h http.Header) string {
+	ct := h.Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return ct
+}
+
+func httpVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// recordingBody keeps a (capped) copy of what the caller reads and reports it
+// once the body is fully read or closed, whichever comes first.
+type recordingBody struct {
+	io.ReadCloser
+
+	buf  bytes.Buffer
+	size int
+
+	once sync.Once
+	done func(body []byte, size int)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	if room := maxBodySize - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	b.size += n
+
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+func (b *recordingBody) finish() {
+	b.once.Do(func() {
+		b.done(b.buf.Bytes(), b.size)
+	})
+}
@@ -0,0 +1,216 @@
+// Package keepalive watches a play's remaining time, warning before it
+// expires and, if asked to, extending its lifetime.
+package keepalive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+const (
+	DefaultInterval = 30 * time.Second
+
+	DefaultExtendBy = 30 * time.Minute
+)
+
+var DefaultWarnAt = []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute}
+
+type Options struct {
+	PlayID string
+
+	// WarnAt lists the remaining times to print a warning at.
+	WarnAt []time.Duration
+
+	// ExtendBy, if positive, makes the guard extend the play's lifetime by
+	// that much every time the remaining time drops below ExtendAt.
+	ExtendBy time.Duration
+
+	// ExtendAt defaults to the largest WarnAt threshold.
+	ExtendAt time.Duration
+
+	// MaxLifetime caps the extensions (zero means no cap). The server
+	// enforces the account's limit regardless.
+	MaxLifetime time.Duration
+
+	Interval time.Duration
+
+	Out labcli.Outputer
+}
+
+type Guard struct {
+	client *api.Client
+	opts   Options
+
+	warned    map[time.Duration]bool
+	canExtend bool
+}
+
+func New(client *api.Client, opts Options) *Guard {
+	opts.WarnAt = slices.Clone(opts.WarnAt)
+	slices.Sort(opts.WarnAt)
+
+	if opts.ExtendAt <= 0 && len(opts.WarnAt) > 0 {
+		opts.ExtendAt = opts.WarnAt[len(opts.WarnAt)-1]
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	return &Guard{
+		client:    client,
+		opts:      opts,
+		warned:    map[time.Duration]bool{},
+		canExtend: opts.ExtendBy > 0,
+	}
+}
+
+// Run watches the play until it's no longer running or the context is
+// cancelled.
+func (g *Guard) Run(ctx context.Context) error {
+	for {
+		play, err := g.client.GetPlay(ctx, g.opts.PlayID)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+
+		case errors.Is(err, api.ErrNotFound):
+			return fmt.Errorf("playground %s not found", g.opts.PlayID)
+
+		case err != nil:
+			slog.Debug("Keepalive couldn't get the playground", "error", err.Error())
+
+		default:
+			if done := g.check(ctx, play); done {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(g.opts.Interval):
+		}
+	}
+}
+
+// Start runs the guard in the background for as long as the context lives,
+// e.g., alongside an SSH session.
+func Start(ctx context.Context, client *api.Client, opts Options) {
+	go func() {
+		if err := New(client, opts).Run(ctx); err != nil {
+			slog.Debug("Keepalive stopped", "error", err.Error())
+		}
+	}()
+}
+
+// AddFlag adds the --keepalive flag of the commands that hold a connection
+// to a playground (ssh, ide, port-forward).
+func AddFlag(flags *pflag.FlagSet, enabled *bool) {
+	flags.BoolVar(
+		enabled,
+		"keepalive",
+		false,
+		`Warn before the playground expires and keep extending its lifetime while connected`,
+	)
+}
+
+// StartSession is what the --keepalive flag does: it starts a guard with the
+// default warnings and extensions that runs until the returned function is
+// called (i.e., until the connection is closed).
+func StartSession(ctx context.Context, cli labcli.CLI, playID string) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	Start(ctx, cli.Client(), Options{
+		PlayID:   playID,
+		WarnAt:   DefaultWarnAt,
+		ExtendBy: DefaultExtendBy,
+		Out:      cli,
+	})
+
+	return cancel
+}
+
+func (g *Guard) check(ctx context.Context, play *api.Play) (done bool) {
+	if !play.IsActive() {
+		g.opts.Out.PrintAux("Playground %s is no longer running.\n", play.ID)
+		return true
+	}
+
+	if g.canExtend && remaining(play) <= g.opts.ExtendAt {
+		if extended := g.extend(ctx, play); extended != nil {
+			play = extended
+		}
+	}
+
+	g.warn(play.ID, remaining(play))
+	return false
+}
+
+func (g *Guard) extend(ctx context.Context, play *api.Play) *api.Play {
+	lifetime, err := time.ParseDuration(play.MaxPlayTime)
+	if err != nil {
+		g.opts.Out.PrintErr("Warning: playground %s lifetime is unknown, not extending it.\n", play.ID)
+		g.canExtend = false
+		return nil
+	}
+
+	target := lifetime + g.opts.ExtendBy
+	if g.opts.MaxLifetime > 0 && target > g.opts.MaxLifetime {
+		target = g.opts.MaxLifetime
+	}
+	if target <= lifetime {
+		g.opts.Out.PrintAux("Playground %s has reached the maximum lifetime of %s, not extending it further.\n", play.ID, lifetime)
+		g.canExtend = false
+		return nil
+	}
+
+	extended, err := g.client.SetPlayMaxPlayTime(ctx, play.ID, int(target.Minutes()))
+	if err != nil {
+		g.opts.Out.PrintErr("Warning: couldn't extend playground %s lifetime: %v\n", play.ID, err)
+
+		// Only "not now" is worth retrying - e.g., the account's limit
+		// won't go away on the next attempt.
+		var apiErr *api.APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() {
+			g.canExtend = false
+		}
+		return nil
+	}
+
+	g.opts.Out.PrintAux("Extended playground %s lifetime to %s (expires in %s).\n",
+		play.ID, target, remaining(extended).Round(time.Second))
+	return extended
+}
+
+// warn prints a warning once per crossed threshold. A threshold can fire
+// again if the lifetime has been extended past it in the meantime.
+func (g *Guard) warn(playID string, left time.Duration) {
+	var crossed bool
+	for _, at := range g.opts.WarnAt {
+		if left > at {
+			g.warned[at] = false
+			continue
+		}
+		if !g.warned[at] {
+			g.warned[at] = true
+			crossed = true
+		}
+	}
+
+	if crossed {
+		g.opts.Out.PrintErr("WARNING: playground %s expires in %s!\n", playID, left.Round(time.Second))
+	}
+}
+
+func remaining(play *api.Play) time.Duration {
+	return time.Duration(play.ExpiresIn) * time.Millisecond
+}
@@ -0,0 +1,130 @@
+package keepalive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/api/apitest"
+)
+
+type recorder struct {
+	out strings.Builder
+	err strings.Builder
+}
+
+func (r *recorder) PrintOut(format string, a ...any) { fmt.Fprintf(&r.out, format, a...) }
+func (r *recorder) PrintErr(format string, a ...any) { fmt.Fprintf(&r.err, format, a...) }
+func (r *recorder) PrintAux(format string, a ...any) { fmt.Fprintf(&r.out, format, a...) }
+
+func startPlay(t *testing.T) (*apitest.Server, *api.Play) {
+	t.Helper()
+
+	s := apitest.NewServer()
+	t.Cleanup(s.Close)
+
+	s.AddPlayground(api.Playground{
+		Name:     "docker",
+		Machines: []api.PlaygroundMachine{{Name: "docker-01"}},
+	})
+
+	play, err := s.Client().CreatePlay(context.Background(), api.CreatePlayRequest{Playground: "docker"})
+	require.NoError(t, err)
+
+	return s, play
+}
+
+func TestWarnings(t *testing.T) {
+	ctx := context.Background()
+	s, play := startPlay(t)
+
+	var out recorder
+	g := New(s.Client(), Options{
+		PlayID: play.ID,
+		WarnAt: []time.Duration{30 * time.Minute, 2 * time.Hour, 90 * time.Minute},
+		Out:    &out,
+	})
+
+	// The play has about an hour left - all thresholds are crossed at
+	// once, but there's only one warning.
+	assert.False(t, g.check(ctx, play))
+	assert.Equal(t, 1, strings.Count(out.err.String(), "WARNING"))
+
+	// No repeated warnings.
+	assert.False(t, g.check(ctx, play))
+	assert.Equal(t, 1, strings.Count(out.err.String(), "WARNING"))
+
+	// Once the lifetime is extended past a threshold, it can fire again.
+	extended, err := s.Client().SetPlayMaxPlayTime(ctx, play.ID, 150)
+	require.NoError(t, err)
+	assert.False(t, g.check(ctx, extended))
+	assert.Equal(t, 1, strings.Count(out.err.String(), "WARNING"))
+
+	assert.False(t, g.check(ctx, play))
+	assert.Equal(t, 2, strings.Count(out.err.String(), "WARNING"))
+
+	// Stopped plays end the watch.
+	stopped, err := s.Client().StopPlay(ctx, play.ID)
+	require.NoError(t, err)
+	assert.True(t, g.check(ctx, stopped))
+}
+
+func TestExtend(t *testing.T) {
+	ctx := context.Background()
+	s, play := startPlay(t)
+
+	var out recorder
+	g := New(s.Client(), Options{
+		PlayID:      play.ID,
+		WarnAt:      []time.Duration{5 * time.Minute},
+		ExtendAt:    3 * time.Hour,
+		ExtendBy:    30 * time.Minute,
+		MaxLifetime: 2 * time.Hour,
+		Out:         &out,
+	})
+
+	current := func() *api.Play {
+		p, err := s.Client().GetPlay(ctx, play.ID)
+		require.NoError(t, err)
+		return p
+	}
+
+	g.check(ctx, current())
+	assert.Equal(t, "1h30m0s", current().MaxPlayTime)
+
+	g.check(ctx, current())
+	assert.Equal(t, "2h0m0s", current().MaxPlayTime)
+
+	// Capped by MaxLifetime.
+	g.check(ctx, current())
+	assert.Equal(t, "2h0m0s", current().MaxPlayTime)
+	assert.False(t, g.canExtend)
+	assert.Contains(t, out.out.String(), "maximum lifetime")
+
+	// Far from expiring, so no warnings.
+	assert.Empty(t, out.err.String())
+}
+
+func TestRun(t *testing.T) {
+	s, play := startPlay(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.Client().StopPlay(ctx, play.ID)
+	require.NoError(t, err)
+
+	var out recorder
+	err = New(s.Client(), Options{PlayID: play.ID, Interval: 10 * time.Millisecond, Out: &out}).Run(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, out.out.String(), "no longer running")
+
+	err = New(s.Client(), Options{PlayID: "nope", Interval: 10 * time.Millisecond, Out: &out}).Run(ctx)
+	assert.Error(t, err)
+}
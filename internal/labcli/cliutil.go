@@ -52,6 +52,9 @@ type CLI interface {
 
 	Input(title, prompt string, value *string, validate func(string) error) error
 
+	// Password is an Input that doesn't echo what's typed.
+	Password(title string, value *string) error
+
 	Version() string
 }
 
@@ -154,6 +157,10 @@ func (c *cli) Input(
 	return huh.NewInput().Title(title).Prompt(prompt).Validate(validate).Value(value).Run()
 }
 
+func (c *cli) Password(title string, value *string) error {
+	return huh.NewInput().Title(title).EchoMode(huh.EchoModePassword).Value(value).Run()
+}
+
 func (c *cli) Version() string {
 	return c.version
 }
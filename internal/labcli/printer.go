@@ -1,6 +1,7 @@
 package labcli
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -93,6 +94,39 @@ func (p *tablePrinter[T, U]) Flush() {
 	p.writer.Flush()
 }
 
+type csvPrinter[T any, U []T] struct {
+	header  []string
+	rowFunc func(T) []string
+	writer  *csv.Writer
+}
+
+// NewCSVPrinter outputs values as CSV, one row per item.
+func NewCSVPrinter[T any, U []T](w io.Writer, header []string, rowFunc func(T) []string) Printer[T, U] {
+	return &csvPrinter[T, U]{
+		header:  header,
+		rowFunc: rowFunc,
+		writer:  csv.NewWriter(w),
+	}
+}
+
+func (p *csvPrinter[T, U]) Print(items U) error {
+	if err := p.writer.Write(p.header); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := p.writer.Write(p.rowFunc(item)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *csvPrinter[T, U]) Flush() {
+	p.writer.Flush()
+}
+
 type jsonPrinter[T any, U []T | map[string]T] struct {
 	encoder *json.Encoder
 }
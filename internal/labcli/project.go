@@ -0,0 +1,30 @@
+package labcli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/config"
+)
+
+// ProjectDefault fills in the flag's value from the project config
+// (.labctl.yaml) unless the flag is set explicitly.
+func ProjectDefault(cmd *cobra.Command, flag string, value *string, projectValue string) {
+	if projectValue != "" && !cmd.Flags().Changed(flag) {
+		*value = projectValue
+	}
+}
+
+// ProjectDefaults is ProjectDefault for repeatable flags.
+func ProjectDefaults(cmd *cobra.Command, flag string, value *[]string, projectValue []string) {
+	if len(projectValue) > 0 && !cmd.Flags().Changed(flag) {
+		*value = projectValue
+	}
+}
+
+// IsProjectPlay tells whether the project config's machine, user, etc.
+// defaults apply to the play - they are only meant for the plays of the
+// project's own playground.
+func IsProjectPlay(project config.ProjectConfig, play *api.Play) bool {
+	return project.Playground != "" && play.Playground.Name == project.Playground
+}
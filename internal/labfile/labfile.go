@@ -0,0 +1,202 @@
+// Package labfile describes a playground environment declaratively - what
+// 'labctl up' brings a play to and 'labctl down' tears down.
+package labfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const DefaultFileName = "labfile.yaml"
+
+type Labfile struct {
+	// FilePath is where the labfile was loaded from. Relative local paths
+	// in it are resolved against its directory.
+	FilePath string `yaml:"-"`
+
+	Playground Playground `yaml:"playground"`
+
+	Title string `yaml:"title,omitempty"`
+
+	// Machine and User are the defaults for the steps below.
+	Machine string `yaml:"machine,omitempty"`
+
+	User string `yaml:"user,omitempty"`
+
+	Files []File `yaml:"files,omitempty"`
+
+	Setup []SetupStep `yaml:"setup,omitempty"`
+
+	PortForwards []PortForward `yaml:"portForwards,omitempty"`
+
+	Expose []ExposedPort `yaml:"expose,omitempty"`
+}
+
+// Playground is either just a name:
+//
+//	playground: docker
+//
+// or a name of the base playground with an inline spec:
+//
+//	playground:
+//	  name: ubuntu-24-04
+//	  machines: [...]
+//	  tabs: [...]
+type Playground struct {
+	Name string `yaml:"name"`
+
+	api.PlaygroundSpec `yaml:",inline"`
+}
+
+func (p *Playground) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&p.Name)
+	}
+
+	type plain Playground
+	return node.Decode((*plain)(p))
+}
+
+func (p *Playground) HasSpec() bool {
+	return len(p.Machines) > 0 || len(p.Tabs) > 0 || len(p.Networks) > 0 || len(p.InitTasks) > 0
+}
+
+type File struct {
+	// Source is a local file or directory.
+	Source string `yaml:"source"`
+
+	// Target is a path on the playground machine.
+	Target string `yaml:"target"`
+
+	Machine string `yaml:"machine,omitempty"`
+
+	User string `yaml:"user,omitempty"`
+}
+
+type SetupStep struct {
+	Run string `yaml:"run"`
+
+	Machine string `yaml:"machine,omitempty"`
+
+	User string `yaml:"user,omitempty"`
+}
+
+// ID identifies the step across 'labctl up' runs, so that each step runs
+// only once per play. Changing the step makes it a new one.
+func (s SetupStep) ID() string {
+	return digest(s)
+}
+
+// PortForward uses the -L and -R flag syntax of 'labctl port-forward' - only
+// one of Local and Remote is expected.
+type PortForward struct {
+	Local string `yaml:"local,omitempty"`
+
+	Remote string `yaml:"remote,omitempty"`
+
+	Machine string `yaml:"machine,omitempty"`
+}
+
+type ExposedPort struct {
+	Port int `yaml:"port"`
+
+	Machine string `yaml:"machine,omitempty"`
+
+	Public bool `yaml:"public,omitempty"`
+
+	TLS bool `yaml:"tls,omitempty"`
+
+	HostRewrite string `yaml:"hostRewrite,omitempty"`
+
+	PathRewrite string `yaml:"pathRewrite,omitempty"`
+}
+
+func Load(path string) (*Labfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read labfile: %w", err)
+	}
+
+	var lf Labfile
+	if err := yaml.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("unable to decode labfile %s: %w", path, err)
+	}
+
+	lf.FilePath, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lf.validate(); err != nil {
+		return nil, fmt.Errorf("invalid labfile %s: %w", path, err)
+	}
+
+	return &lf, nil
+}
+
+func (lf *Labfile) validate() error {
+	if lf.Playground.Name == "" {
+		return fmt.Errorf("playground name is required")
+	}
+
+	for i, f := range lf.Files {
+		if f.Source == "" || f.Target == "" {
+			return fmt.Errorf("files[%d]: both source and target are required", i)
+		}
+	}
+
+	for i, s := range lf.Setup {
+		if strings.TrimSpace(s.Run) == "" {
+			return fmt.Errorf("setup[%d]: run is required", i)
+		}
+	}
+
+	for i, pf := range lf.PortForwards {
+		if (pf.Local == "") == (pf.Remote == "") {
+			return fmt.Errorf("portForwards[%d]: exactly one of local and remote is required", i)
+		}
+	}
+
+	for i, p := range lf.Expose {
+		if p.Port <= 0 || p.Port > 65535 {
+			return fmt.Errorf("expose[%d]: invalid port %d", i, p.Port)
+		}
+	}
+
+	return nil
+}
+
+// Dir is where relative local paths are resolved against.
+func (lf *Labfile) Dir() string {
+	return filepath.Dir(lf.FilePath)
+}
+
+// LocalPath resolves a local path from the labfile.
+func (lf *Labfile) LocalPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(lf.Dir(), path)
+}
+
+// PlaygroundDigest changes whenever the playground definition does. A play
+// started from a different definition can't be reused.
+func (lf *Labfile) PlaygroundDigest() string {
+	return digest(lf.Playground)
+}
+
+func digest(v any) string {
+	// JSON, unlike YAML, is stable for maps (keys are sorted).
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,144 @@
+package labfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLabfile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	lf, err := Load(writeLabfile(t, `
+playground: docker
+machine: docker-01
+files:
+  - source: ./app
+    target: ~/app
+setup:
+  - run: make -C ~/app deps
+portForwards:
+  - local: "8080"
+expose:
+  - port: 8080
+    public: true
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "docker", lf.Playground.Name)
+	assert.False(t, lf.Playground.HasSpec())
+	assert.Equal(t, filepath.Join(lf.Dir(), "app"), lf.LocalPath(lf.Files[0].Source))
+	assert.Equal(t, "/abs/path", lf.LocalPath("/abs/path"))
+	assert.Equal(t, "8080", lf.PortForwards[0].Local)
+	assert.True(t, lf.Expose[0].Public)
+
+	lf, err = Load(writeLabfile(t, `
+playground:
+  name: ubuntu-24-04
+  machines:
+    - name: dev
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ubuntu-24-04", lf.Playground.Name)
+	assert.True(t, lf.Playground.HasSpec())
+	assert.Equal(t, "dev", lf.Playground.Machines[0].Name)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"no playground": `title: foo`,
+		"no file target": `
+playground: docker
+files:
+  - source: ./app
+`,
+		"empty setup step": `
+playground: docker
+setup:
+  - run: " "
+`,
+		"both local and remote": `
+playground: docker
+portForwards:
+  - local: "8080"
+    remote: "9090"
+`,
+		"bad port": `
+playground: docker
+expose:
+  - port: 70000
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeLabfile(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDigests(t *testing.T) {
+	a, err := Load(writeLabfile(t, "playground: docker\nsetup:\n  - run: echo hi\n"))
+	require.NoError(t, err)
+
+	b, err := Load(writeLabfile(t, "playground: docker\ntitle: other\nsetup:\n  - run: echo hi\n"))
+	require.NoError(t, err)
+
+	c, err := Load(writeLabfile(t, "playground: k3s\nsetup:\n  - run: echo bye\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, a.PlaygroundDigest(), b.PlaygroundDigest())
+	assert.NotEqual(t, a.PlaygroundDigest(), c.PlaygroundDigest())
+
+	assert.Equal(t, a.Setup[0].ID(), b.Setup[0].ID())
+	assert.NotEqual(t, a.Setup[0].ID(), c.Setup[0].ID())
+}
+
+func TestState(t *testing.T) {
+	lf, err := Load(writeLabfile(t, "playground: docker\nsetup:\n  - run: echo hi\n"))
+	require.NoError(t, err)
+
+	path := StatePath(lf)
+	assert.Equal(t, filepath.Join(lf.Dir(), ".labctl", "state.json"), path)
+
+	state, err := LoadState(path)
+	require.NoError(t, err)
+	assert.Empty(t, state.PlayID)
+	assert.False(t, state.IsSetupDone(lf.Setup[0]))
+
+	state.PlayID = "play-1"
+	state.PlaygroundDigest = lf.PlaygroundDigest()
+	state.SetupDone = append(state.SetupDone, lf.Setup[0].ID())
+	state.PortForwards = append(state.PortForwards, ForwardedPort{Kind: "local", Machine: "docker-01", Spec: "8080"})
+	state.SetExposedPort(ExposedPortState{ID: "p1", Machine: "docker-01", Port: 8080, URL: "https://old"})
+	state.SetExposedPort(ExposedPortState{ID: "p1", Machine: "docker-01", Port: 8080, URL: "https://new"})
+	require.NoError(t, state.Save())
+
+	loaded, err := LoadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, "play-1", loaded.PlayID)
+	assert.True(t, loaded.IsSetupDone(lf.Setup[0]))
+	assert.True(t, loaded.HasPortForward(ForwardedPort{Kind: "local", Machine: "docker-01", Spec: "8080"}))
+	require.Len(t, loaded.ExposedPorts, 1)
+	assert.Equal(t, "https://new", loaded.ExposedPorts[0].URL)
+
+	loaded.Reset()
+	assert.Empty(t, loaded.PlayID)
+	assert.Equal(t, path, loaded.Path())
+
+	require.NoError(t, loaded.Remove())
+	require.NoError(t, loaded.Remove())
+
+	state, err = LoadState(path)
+	require.NoError(t, err)
+	assert.Empty(t, state.PlayID)
+}
@@ -0,0 +1,128 @@
+package labfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// State is what 'labctl up' has done so far. It lives next to the labfile
+// (in .labctl/state.json), so every project directory gets its own play.
+type State struct {
+	path string
+
+	PlayID string `json:"playId"`
+
+	Playground string `json:"playground"`
+
+	PlaygroundDigest string `json:"playgroundDigest"`
+
+	// SetupDone lists the IDs of the setup steps completed in the play.
+	SetupDone []string `json:"setupDone,omitempty"`
+
+	PortForwards []ForwardedPort `json:"portForwards,omitempty"`
+
+	ExposedPorts []ExposedPortState `json:"exposedPorts,omitempty"`
+}
+
+type ForwardedPort struct {
+	Kind string `json:"kind"`
+
+	Machine string `json:"machine"`
+
+	Spec string `json:"spec"`
+}
+
+type ExposedPortState struct {
+	ID string `json:"id"`
+
+	Machine string `json:"machine"`
+
+	Port int `json:"port"`
+
+	URL string `json:"url"`
+}
+
+func StatePath(lf *Labfile) string {
+	return filepath.Join(lf.Dir(), ".labctl", "state.json")
+}
+
+// LoadState returns an empty state if 'labctl up' hasn't been run yet (or
+// the environment has been torn down).
+func LoadState(path string) (*State, error) {
+	state := &State{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to decode state file %s: %w", path, err)
+	}
+
+	return state, nil
+}
+
+func (s *State) Path() string {
+	return s.path
+}
+
+// Reset forgets everything about the current play.
+func (s *State) Reset() {
+	*s = State{path: s.path}
+}
+
+func (s *State) IsSetupDone(step SetupStep) bool {
+	return slices.Contains(s.SetupDone, step.ID())
+}
+
+func (s *State) HasPortForward(pf ForwardedPort) bool {
+	return slices.Contains(s.PortForwards, pf)
+}
+
+// SetExposedPort records the port, replacing the previous record for the
+// same machine and port number.
+func (s *State) SetExposedPort(port ExposedPortState) {
+	for i, p := range s.ExposedPorts {
+		if p.Machine == port.Machine && p.Port == port.Port {
+			s.ExposedPorts[i] = port
+			return
+		}
+	}
+	s.ExposedPorts = append(s.ExposedPorts, port)
+}
+
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("unable to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("unable to write state file: %w", err)
+	}
+
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		return fmt.Errorf("unable to rename state file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *State) Remove() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove state file: %w", err)
+	}
+	return nil
+}
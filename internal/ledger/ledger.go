@@ -0,0 +1,158 @@
+// Package ledger keeps a local record of the plays labctl starts, stops, and
+// destroys, so that 'labctl usage' can tell how much playground time went
+// where. The server doesn't expose this history to the CLI.
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+const fileName = "usage.jsonl"
+
+type Action string
+
+const (
+	ActionStart   Action = "start"
+	ActionRestart Action = "restart"
+	ActionStop    Action = "stop"
+	ActionDestroy Action = "destroy"
+
+	// ActionSync entries carry the state events refreshed by 'labctl usage'.
+	ActionSync Action = "sync"
+)
+
+// Entry is a single line of the ledger.
+type Entry struct {
+	At     time.Time `json:"at"`
+	Action Action    `json:"action"`
+
+	PlayID      string `json:"playId"`
+	Playground  string `json:"playground,omitempty"`
+	Title       string `json:"title,omitempty"`
+	MaxPlayTime string `json:"maxPlayTime,omitempty"`
+
+	// Project is the directory labctl was run in (or the one with the
+	// .labctl.yaml file, if any).
+	Project string `json:"project,omitempty"`
+
+	StateEvents []api.StateEvent `json:"stateEvents,omitempty"`
+}
+
+// Path returns the ledger's location. It's kept in the plays directory, so
+// every config context (i.e., account) gets its own ledger.
+func Path(playsDir string) string {
+	return filepath.Join(playsDir, fileName)
+}
+
+func NewEntry(action Action, play *api.Play, project string) Entry {
+	e := Entry{
+		At:          time.Now().UTC(),
+		Action:      action,
+		PlayID:      play.ID,
+		Playground:  play.Playground.Name,
+		Title:       play.Title,
+		MaxPlayTime: play.MaxPlayTime,
+		Project:     project,
+	}
+	if play.Status != nil {
+		e.StateEvents = play.Status.StateEvents
+	}
+	return e
+}
+
+// Append adds the entries to the end of the ledger, creating it if needed.
+func Append(path string, entries ...Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create ledger directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open ledger: %w", err)
+	}
+	defer file.Close()
+
+	// One write per entry keeps concurrent labctl processes from
+	// interleaving their lines.
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("unable to write ledger: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Load reads all the ledger entries. Lines that can't be decoded (e.g., a
+// write cut short) are skipped.
+func Load(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open ledger: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			slog.Debug("Skipping malformed ledger line", "path", path, "line", n, "error", err.Error())
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read ledger: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Record appends an entry for the play to the active context's ledger.
+// Failures are only logged - the ledger must never get in the way of the
+// command that's being run.
+func Record(cli labcli.CLI, action Action, play *api.Play) {
+	if play == nil || play.ID == "" {
+		return
+	}
+
+	if err := Append(Path(cli.Config().PlaysDir), NewEntry(action, play, projectDir(cli))); err != nil {
+		slog.Debug("Couldn't record the play in the usage ledger", "play", play.ID, "error", err.Error())
+	}
+}
+
+func projectDir(cli labcli.CLI) string {
+	if path := cli.Config().Project.FilePath; path != "" {
+		return filepath.Dir(path)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,141 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func events(states ...any) []api.StateEvent {
+	var evs []api.StateEvent
+	for i := 0; i < len(states); i += 2 {
+		evs = append(evs, api.StateEvent{
+			State: states[i].(api.PlayState),
+			At:    states[i+1].(time.Time).Format(time.RFC3339),
+		})
+	}
+	return evs
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := Path(filepath.Join(t.TempDir(), "plays"))
+
+	entries, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	play := &api.Play{ID: "p1", Title: "dev", Status: &api.PlayStatus{StateEvents: events(api.StateRunning, time.Now())}}
+	play.Playground.Name = "k3s"
+
+	require.NoError(t, Append(path, NewEntry(ActionStart, play, "/src/app")))
+	require.NoError(t, Append(path, NewEntry(ActionStop, &api.Play{ID: "p1"}, "/src/app")))
+
+	// A write cut short doesn't make the whole ledger unreadable.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"at":"2026-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err = Load(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ActionStart, entries[0].Action)
+	assert.Equal(t, "k3s", entries[0].Playground)
+	assert.Equal(t, "/src/app", entries[0].Project)
+	assert.Len(t, entries[0].StateEvents, 1)
+	assert.Equal(t, ActionStop, entries[1].Action)
+}
+
+func TestIntervals(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	now := t0.Add(24 * time.Hour)
+
+	// Stopped, restarted, and destroyed.
+	u := &PlayUsage{StateEvents: events(
+		api.StateCreated, t0,
+		api.StateRunning, t0.Add(time.Minute),
+		api.StateStopping, t0.Add(61*time.Minute),
+		api.StateStopped, t0.Add(62*time.Minute),
+		api.StateRunning, t0.Add(2*time.Hour),
+		api.StateDestroyed, t0.Add(150*time.Minute),
+	)}
+	assert.Equal(t, []Interval{
+		{Start: t0.Add(time.Minute), End: t0.Add(61 * time.Minute)},
+		{Start: t0.Add(2 * time.Hour), End: t0.Add(150 * time.Minute)},
+	}, u.Intervals(now))
+	assert.True(t, u.Settled())
+
+	// Still running according to the events, but labctl destroyed it.
+	u = &PlayUsage{
+		StateEvents: events(api.StateRunning, t0),
+		StoppedAt:   []time.Time{t0.Add(-time.Hour), t0.Add(30 * time.Minute)},
+	}
+	assert.Equal(t, 30*time.Minute, u.Intervals(now)[0].Duration())
+	assert.False(t, u.Settled())
+
+	// Not heard of since it started - capped by the lifetime.
+	u = &PlayUsage{StateEvents: events(api.StateRunning, t0), MaxPlayTime: 2 * time.Hour}
+	assert.Equal(t, 2*time.Hour, u.Intervals(now)[0].Duration())
+
+	// ...or by now.
+	u = &PlayUsage{StateEvents: events(api.StateRunning, t0)}
+	assert.Equal(t, 24*time.Hour, u.Intervals(now)[0].Duration())
+}
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	now := day.Add(72 * time.Hour)
+
+	entries := []Entry{
+		{
+			Action: ActionStart, PlayID: "p1", Playground: "k3s", Project: "/src/a",
+			StateEvents: events(api.StateRunning, day.Add(23*time.Hour)),
+		},
+		{
+			Action: ActionStart, PlayID: "p2", Playground: "docker", Project: "/src/b",
+			StateEvents: events(api.StateRunning, day.Add(10*time.Hour), api.StateStopped, day.Add(11*time.Hour)),
+		},
+		{
+			Action: ActionStart, PlayID: "p3", Playground: "k3s", Project: "/src/a",
+			StateEvents: events(api.StateRunning, day.Add(12*time.Hour), api.StateStopped, day.Add(13*time.Hour)),
+		},
+		// Synced later on: p1 ran across midnight.
+		{
+			Action: ActionSync, PlayID: "p1", Playground: "k3s",
+			StateEvents: events(api.StateRunning, day.Add(23*time.Hour), api.StateDestroyed, day.Add(26*time.Hour)),
+		},
+	}
+
+	usages := Fold(entries)
+	require.Len(t, usages, 3)
+	assert.Equal(t, "/src/a", usages[0].Project)
+
+	hours := func(rows []Summary) map[string]float64 {
+		m := map[string]float64{}
+		for _, r := range rows {
+			m[r.Key] = r.Hours
+		}
+		return m
+	}
+
+	byPlayground := Summarize(usages, ByPlayground, time.Time{}, now)
+	assert.Equal(t, map[string]float64{"k3s": 4, "docker": 1}, hours(byPlayground))
+	assert.Equal(t, "k3s", byPlayground[0].Key)
+	assert.Equal(t, 2, byPlayground[0].Plays)
+
+	assert.Equal(t, map[string]float64{"/src/a": 4, "/src/b": 1}, hours(Summarize(usages, ByProject, time.Time{}, now)))
+
+	byDay := Summarize(usages, ByDay, time.Time{}, now)
+	assert.Equal(t, map[string]float64{"2026-10-01": 3, "2026-10-02": 2}, hours(byDay))
+	assert.Equal(t, "2026-10-01", byDay[0].Key)
+
+	// Only the time since the given moment counts.
+	assert.Equal(t, map[string]float64{"k3s": 2}, hours(Summarize(usages, ByPlayground, day.Add(24*time.Hour), now)))
+}
@@ -0,0 +1,236 @@
+package ledger
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+// PlayUsage is the history of a single play folded from its ledger entries.
+type PlayUsage struct {
+	PlayID     string
+	Playground string
+	Title      string
+	Project    string
+
+	MaxPlayTime time.Duration
+
+	// StateEvents come from the latest entry that has any.
+	StateEvents []api.StateEvent
+
+	// StoppedAt lists when labctl stopped or destroyed the play.
+	StoppedAt []time.Time
+}
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Fold groups the entries by play, in the order the plays first appear. The
+// project is the one the play was started from.
+func Fold(entries []Entry) []*PlayUsage {
+	var (
+		usages []*PlayUsage
+		byID   = map[string]*PlayUsage{}
+	)
+
+	for _, e := range entries {
+		u, ok := byID[e.PlayID]
+		if !ok {
+			u = &PlayUsage{PlayID: e.PlayID}
+			byID[e.PlayID] = u
+			usages = append(usages, u)
+		}
+
+		u.Playground = cmp.Or(u.Playground, e.Playground)
+		u.Project = cmp.Or(u.Project, e.Project)
+		u.Title = cmp.Or(e.Title, u.Title)
+
+		if d, err := time.ParseDuration(e.MaxPlayTime); err == nil {
+			u.MaxPlayTime = d
+		}
+		if len(e.StateEvents) > 0 {
+			u.StateEvents = e.StateEvents
+		}
+		if e.Action == ActionStop || e.Action == ActionDestroy {
+			u.StoppedAt = append(u.StoppedAt, e.At)
+		}
+	}
+
+	return usages
+}
+
+// Settled reports whether the play is known to have stopped for good (as
+// far as its recorded state events tell), i.e., there is no point in asking
+// the server for fresher ones.
+func (u *PlayUsage) Settled() bool {
+	if len(u.StateEvents) == 0 {
+		return false
+	}
+
+	switch u.StateEvents[len(u.StateEvents)-1].State {
+	case api.StateDestroyed, api.StateFailed:
+		return true
+	}
+	return false
+}
+
+// Intervals returns the periods the play was running. A period the state
+// events leave open is closed by the next stop or destroy labctl recorded,
+// by the play's lifetime running out, or by now - whichever comes first.
+func (u *PlayUsage) Intervals(now time.Time) []Interval {
+	var (
+		intervals []Interval
+		start     time.Time
+		running   bool
+	)
+
+	for _, ev := range u.StateEvents {
+		at, err := time.Parse(time.RFC3339, ev.At)
+		if err != nil {
+			continue
+		}
+
+		switch ev.State {
+		case api.StateRunning:
+			if !running {
+				start, running = at, true
+			}
+
+		case api.StateStopping, api.StateStopped, api.StateDestroying, api.StateDestroyed, api.StateFailed:
+			if running {
+				intervals = append(intervals, Interval{Start: start, End: at})
+				running = false
+			}
+		}
+	}
+
+	if running {
+		end := now
+		for _, at := range u.StoppedAt {
+			if at.After(start) && at.Before(end) {
+				end = at
+			}
+		}
+		if u.MaxPlayTime > 0 && start.Add(u.MaxPlayTime).Before(end) {
+			end = start.Add(u.MaxPlayTime)
+		}
+		if end.After(start) {
+			intervals = append(intervals, Interval{Start: start, End: end})
+		}
+	}
+
+	return intervals
+}
+
+type GroupBy string
+
+const (
+	ByPlayground GroupBy = "playground"
+	ByDay        GroupBy = "day"
+	ByProject    GroupBy = "project"
+)
+
+// Summary is a row of the usage report.
+type Summary struct {
+	Key      string        `json:"key"`
+	Plays    int           `json:"plays"`
+	Duration time.Duration `json:"-"`
+	Hours    float64       `json:"hours"`
+}
+
+// Summarize totals the running time of the plays within [since, now],
+// grouped as requested. Days are calendar days in the local time zone.
+func Summarize(usages []*PlayUsage, by GroupBy, since, now time.Time) []Summary {
+	var (
+		rows  []*Summary
+		byKey = map[string]*Summary{}
+		plays = map[string]map[string]bool{}
+	)
+
+	add := func(key, playID string, d time.Duration) {
+		row, ok := byKey[key]
+		if !ok {
+			row = &Summary{Key: key}
+			byKey[key] = row
+			plays[key] = map[string]bool{}
+			rows = append(rows, row)
+		}
+
+		row.Duration += d
+		if !plays[key][playID] {
+			plays[key][playID] = true
+			row.Plays++
+		}
+	}
+
+	for _, u := range usages {
+		for _, iv := range u.Intervals(now) {
+			iv, ok := clip(iv, since, now)
+			if !ok {
+				continue
+			}
+
+			switch by {
+			case ByDay:
+				for _, day := range splitDays(iv) {
+					add(day.Start.Local().Format(time.DateOnly), u.PlayID, day.Duration())
+				}
+			case ByProject:
+				add(cmp.Or(u.Project, "(unknown)"), u.PlayID, iv.Duration())
+			default:
+				add(cmp.Or(u.Playground, "(unknown)"), u.PlayID, iv.Duration())
+			}
+		}
+	}
+
+	if by == ByDay {
+		slices.SortFunc(rows, func(a, b *Summary) int {
+			return cmp.Compare(a.Key, b.Key)
+		})
+	} else {
+		slices.SortFunc(rows, func(a, b *Summary) int {
+			return cmp.Or(cmp.Compare(b.Duration, a.Duration), cmp.Compare(a.Key, b.Key))
+		})
+	}
+
+	summaries := make([]Summary, 0, len(rows))
+	for _, row := range rows {
+		row.Hours = row.Duration.Hours()
+		summaries = append(summaries, *row)
+	}
+	return summaries
+}
+
+func clip(iv Interval, since, until time.Time) (Interval, bool) {
+	if !since.IsZero() && iv.Start.Before(since) {
+		iv.Start = since
+	}
+	if iv.End.After(until) {
+		iv.End = until
+	}
+	return iv, iv.End.After(iv.Start)
+}
+
+func splitDays(iv Interval) []Interval {
+	var days []Interval
+	for start := iv.Start; start.Before(iv.End); {
+		y, m, d := start.Local().Date()
+		midnight := time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+
+		end := iv.End
+		if midnight.Before(end) {
+			end = midnight
+		}
+		days = append(days, Interval{Start: start, End: end})
+		start = end
+	}
+	return days
+}
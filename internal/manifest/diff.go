@@ -0,0 +1,236 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+// Operation is a single JSON Patch (RFC 6902) operation.
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// JSONPatch returns the operations turning the remote manifest into the
+// local one. Lists of objects with unique names (machines, networks, tabs,
+// users, etc.) are matched by name rather than by position, so reordering
+// them isn't a change. The paths of the elements that stay refer to their
+// remote positions - the removals come after the other changes of the list
+// (from the end), and the additions are appended.
+func JSONPatch(remote, local *api.PlaygroundManifest) ([]Operation, error) {
+	a, err := toTree(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := toTree(local)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	diffTrees("", a, b, &ops)
+	return ops, nil
+}
+
+// UnifiedDiff renders both manifests as YAML, with the named lists of the
+// local one in the remote order, and diffs them line by line. An empty
+// string means no changes.
+func UnifiedDiff(remote, local *api.PlaygroundManifest, remoteName, localName string) (string, error) {
+	a, err := toTree(remote)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := toTree(local)
+	if err != nil {
+		return "", err
+	}
+
+	remoteYAML, err := yaml.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+
+	localYAML, err := yaml.Marshal(align(a, b))
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(remoteYAML)),
+		B:        difflib.SplitLines(string(localYAML)),
+		FromFile: remoteName,
+		ToFile:   localName,
+		Context:  3,
+	})
+}
+
+// toTree turns the manifest into plain maps and slices, with the JSON names
+// of the fields - the ones the server and JSON Patch paths use.
+func toTree(m *api.PlaygroundManifest) (any, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode manifest: %w", err)
+	}
+
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("couldn't decode manifest: %w", err)
+	}
+	return tree, nil
+}
+
+func diffTrees(path string, a, b any, ops *[]Operation) {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := map[string]bool{}
+		for k := range a {
+			keys[k] = true
+		}
+		for k := range b {
+			keys[k] = true
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			p := path + "/" + escapePointer(k)
+
+			av, inA := a[k]
+			bv, inB := b[k]
+			switch {
+			case !inB:
+				*ops = append(*ops, Operation{Op: "remove", Path: p})
+			case !inA:
+				*ops = append(*ops, Operation{Op: "add", Path: p, Value: bv})
+			default:
+				diffTrees(p, av, bv, ops)
+			}
+		}
+		return
+
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			break
+		}
+
+		if names(a) != nil && names(b) != nil {
+			diffNamedLists(path, a, b, ops)
+			return
+		}
+
+		if len(a) == len(b) {
+			for i := range a {
+				diffTrees(path+"/"+strconv.Itoa(i), a[i], b[i], ops)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*ops = append(*ops, Operation{Op: "replace", Path: path, Value: b})
+	}
+}
+
+func diffNamedLists(path string, a, b []any, ops *[]Operation) {
+	aNames, bNames := names(a), names(b)
+
+	for i, name := range aNames {
+		if j := slices.Index(bNames, name); j != -1 {
+			diffTrees(path+"/"+strconv.Itoa(i), a[i], b[j], ops)
+		}
+	}
+
+	for i := len(aNames) - 1; i >= 0; i-- {
+		if !slices.Contains(bNames, aNames[i]) {
+			*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+	}
+
+	for j, name := range bNames {
+		if !slices.Contains(aNames, name) {
+			*ops = append(*ops, Operation{Op: "add", Path: path + "/-", Value: b[j]})
+		}
+	}
+}
+
+// align returns b with its named lists in the order of the same lists of a
+// (the elements a doesn't have go last).
+func align(a, b any) any {
+	switch b := b.(type) {
+	case map[string]any:
+		a, _ := a.(map[string]any)
+
+		aligned := make(map[string]any, len(b))
+		for k, v := range b {
+			aligned[k] = align(a[k], v)
+		}
+		return aligned
+
+	case []any:
+		a, _ := a.([]any)
+
+		aNames, bNames := names(a), names(b)
+		if aNames == nil || bNames == nil {
+			return b
+		}
+
+		aligned := make([]any, 0, len(b))
+		for i, name := range aNames {
+			if j := slices.Index(bNames, name); j != -1 {
+				aligned = append(aligned, align(a[i], b[j]))
+			}
+		}
+		for j, name := range bNames {
+			if !slices.Contains(aNames, name) {
+				aligned = append(aligned, b[j])
+			}
+		}
+		return aligned
+	}
+
+	return b
+}
+
+// names returns the names of the list elements if all of them are objects
+// with a unique non-empty name, and nil otherwise.
+func names(list []any) []string {
+	if len(list) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		name, _ := obj["name"].(string)
+		if name == "" || slices.Contains(names, name) {
+			return nil
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
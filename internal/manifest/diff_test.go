@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func testManifests() (*api.PlaygroundManifest, *api.PlaygroundManifest) {
+	remote := &api.PlaygroundManifest{
+		Title: "My K8s",
+		Playground: api.PlaygroundSpec{
+			Machines: []api.PlaygroundMachine{
+				{Name: "cplane-01", Resources: &api.MachineResources{CPUCount: 2, RAMSize: "2Gi"}},
+				{Name: "node-01"},
+				{Name: "node-02"},
+			},
+			Tabs: []api.PlaygroundTab{
+				{Kind: "terminal", Name: "cplane-01", Machine: "cplane-01"},
+			},
+		},
+	}
+
+	// Reordered, with a machine resized, one removed, and one added.
+	local := &api.PlaygroundManifest{
+		Title: "My K8s",
+		Playground: api.PlaygroundSpec{
+			Machines: []api.PlaygroundMachine{
+				{Name: "node-03"},
+				{Name: "node-01"},
+				{Name: "cplane-01", Resources: &api.MachineResources{CPUCount: 2, RAMSize: "4Gi"}},
+			},
+			Tabs: []api.PlaygroundTab{
+				{Kind: "terminal", Name: "cplane-01", Machine: "cplane-01"},
+			},
+		},
+	}
+
+	return remote, local
+}
+
+func TestJSONPatch(t *testing.T) {
+	remote, local := testManifests()
+
+	ops, err := JSONPatch(remote, local)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Operation{
+		{Op: "replace", Path: "/playground/machines/0/resources/ramSize", Value: "4Gi"},
+		{Op: "remove", Path: "/playground/machines/2"},
+		{Op: "add", Path: "/playground/machines/-", Value: map[string]any{"name": "node-03"}},
+	}, ops)
+
+	ops, err = JSONPatch(remote, remote)
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	remote, local := testManifests()
+
+	diff, err := UnifiedDiff(remote, local, "my-k8s", "manifest.yaml")
+	require.NoError(t, err)
+
+	assert.Contains(t, diff, "--- my-k8s\n+++ manifest.yaml\n")
+	assert.Contains(t, diff, "-            ramSize: 2Gi\n+            ramSize: 4Gi\n")
+	assert.Contains(t, diff, "-        - name: node-02\n+        - name: node-03\n")
+
+	// Matched by name - reordering alone isn't a change.
+	local.Playground.Machines = []api.PlaygroundMachine{remote.Playground.Machines[2], remote.Playground.Machines[0], remote.Playground.Machines[1]}
+	diff, err = UnifiedDiff(remote, local, "my-k8s", "manifest.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
@@ -0,0 +1,206 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const (
+	// MaxFileRefSize is the size limit of a single file referenced by
+	// contentFrom or runFrom.
+	MaxFileRefSize = 1 << 20
+
+	// MaxFileRefsSize is the size limit of all the referenced files together.
+	MaxFileRefsSize = 4 << 20
+)
+
+// InlineFiles replaces the contentFrom references of the machines' startup
+// files and the runFrom references of the init tasks with the contents of the
+// files. Relative paths are resolved against dir (usually, the directory of
+// the manifest file).
+func InlineFiles(m *api.PlaygroundManifest, dir string) error {
+	r := &fileReader{dir: dir}
+
+	for i := range m.Playground.Machines {
+		machine := &m.Playground.Machines[i]
+
+		for j := range machine.StartupFiles {
+			file := &machine.StartupFiles[j]
+			if file.ContentFrom == "" {
+				continue
+			}
+
+			if file.Content != "" {
+				return fmt.Errorf("machine %s: startup file %s: content and contentFrom are mutually exclusive", machine.Name, file.Path)
+			}
+
+			content, err := r.read(file.ContentFrom)
+			if err != nil {
+				return fmt.Errorf("machine %s: startup file %s: %w", machine.Name, file.Path, err)
+			}
+			file.Content, file.ContentFrom = content, ""
+		}
+	}
+
+	var names []string
+	for name := range m.Playground.InitTasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		task := m.Playground.InitTasks[name]
+		if task.RunFrom == "" {
+			continue
+		}
+
+		if task.Run != "" {
+			return fmt.Errorf("init task %s: run and runFrom are mutually exclusive", name)
+		}
+
+		run, err := r.read(task.RunFrom)
+		if err != nil {
+			return fmt.Errorf("init task %s: %w", name, err)
+		}
+		task.Run, task.RunFrom = run, ""
+		m.Playground.InitTasks[name] = task
+	}
+
+	return nil
+}
+
+type fileReader struct {
+	dir   string
+	total int64
+}
+
+func (r *fileReader) read(ref string) (string, error) {
+	filePath := ref
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(r.dir, filePath)
+	}
+
+	info, err := os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("file %s not found (relative paths are resolved against the manifest file's directory)", ref)
+	}
+	if err != nil {
+		return "", fmt.Errorf("couldn't read %s: %w", ref, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", ref)
+	}
+
+	if info.Size() > MaxFileRefSize {
+		return "", fmt.Errorf("file %s is too large (%s, the limit is %s)",
+			ref, humanize.IBytes(uint64(info.Size())), humanize.IBytes(MaxFileRefSize))
+	}
+	if r.total += info.Size(); r.total > MaxFileRefsSize {
+		return "", fmt.Errorf("file %s exceeds the total size limit of the referenced files (%s)",
+			ref, humanize.IBytes(MaxFileRefsSize))
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read %s: %w", ref, err)
+	}
+	return string(data), nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SplitFiles is the reverse of InlineFiles: it moves the startup files'
+// content and the init tasks' scripts out of the manifest, replacing them with
+// contentFrom and runFrom references. The returned map holds the contents of
+// the files by their paths relative to the manifest file:
+//
+//	files/<machine>/<startup-file-path>
+//	tasks/<task-name>.sh
+func SplitFiles(m *api.PlaygroundManifest) map[string]string {
+	files := map[string]string{}
+
+	for i := range m.Playground.Machines {
+		machine := &m.Playground.Machines[i]
+
+		for j := range machine.StartupFiles {
+			file := &machine.StartupFiles[j]
+			if file.Content == "" {
+				continue
+			}
+
+			ref := uniqueRef(files, path.Join(
+				"files",
+				safeName(machine.Name),
+				safeFilePath(file.Path),
+			))
+			files[ref] = file.Content
+			file.Content, file.ContentFrom = "", "./"+ref
+		}
+	}
+
+	var names []string
+	for name := range m.Playground.InitTasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		task := m.Playground.InitTasks[name]
+		if task.Run == "" {
+			continue
+		}
+
+		ref := uniqueRef(files, path.Join("tasks", safeName(name)+".sh"))
+		files[ref] = task.Run
+		task.Run, task.RunFrom = "", "./"+ref
+		m.Playground.InitTasks[name] = task
+	}
+
+	return files
+}
+
+func safeName(name string) string {
+	name = unsafeNameChars.ReplaceAllString(name, "_")
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+func safeFilePath(p string) string {
+	var parts []string
+	for _, part := range strings.Split(path.Clean("/"+p), "/") {
+		if part != "" {
+			parts = append(parts, safeName(part))
+		}
+	}
+	if len(parts) == 0 {
+		return "_"
+	}
+	return path.Join(parts...)
+}
+
+func uniqueRef(files map[string]string, ref string) string {
+	if _, ok := files[ref]; !ok {
+		return ref
+	}
+
+	ext := path.Ext(ref)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", strings.TrimSuffix(ref, ext), i, ext)
+		if _, ok := files[candidate]; !ok {
+			return candidate
+		}
+	}
+}
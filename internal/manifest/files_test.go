@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func testFilesManifest() *api.PlaygroundManifest {
+	return &api.PlaygroundManifest{
+		Kind: "playground",
+		Playground: api.PlaygroundSpec{
+			Machines: []api.PlaygroundMachine{{
+				Name: "node-01",
+				StartupFiles: []api.MachineStartupFile{
+					{Path: "/etc/profile.d/env.sh", Content: "export FOO=bar\n"},
+					{Path: "/home/laborant/.bashrc", Content: "alias k=kubectl\n"},
+					{Path: "/etc/empty"},
+				},
+			}},
+			InitTasks: map[string]api.InitTask{
+				"install":  {Name: "install", Run: "apt-get install -y jq\n"},
+				"init/k8s": {Name: "init/k8s", Run: "kubeadm init\n"},
+			},
+		},
+	}
+}
+
+func TestSplitAndInlineFiles(t *testing.T) {
+	m := testFilesManifest()
+
+	files := SplitFiles(m)
+	assert.Equal(t, map[string]string{
+		"files/node-01/etc/profile.d/env.sh":  "export FOO=bar\n",
+		"files/node-01/home/laborant/.bashrc": "alias k=kubectl\n",
+		"tasks/install.sh":                    "apt-get install -y jq\n",
+		"tasks/init_k8s.sh":                   "kubeadm init\n",
+	}, files)
+
+	assert.Equal(t, "./files/node-01/etc/profile.d/env.sh", m.Playground.Machines[0].StartupFiles[0].ContentFrom)
+	assert.Empty(t, m.Playground.Machines[0].StartupFiles[0].Content)
+	assert.Empty(t, m.Playground.Machines[0].StartupFiles[2].ContentFrom)
+	assert.Equal(t, "./tasks/install.sh", m.Playground.InitTasks["install"].RunFrom)
+	assert.Empty(t, m.Playground.InitTasks["install"].Run)
+
+	dir := t.TempDir()
+	for path, content := range files {
+		dest := filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(dest), 0755))
+		require.NoError(t, os.WriteFile(dest, []byte(content), 0644))
+	}
+
+	require.NoError(t, InlineFiles(m, dir))
+	assert.Equal(t, testFilesManifest(), m)
+}
+
+func TestInlineFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "big.sh"), []byte(strings.Repeat("x", MaxFileRefSize+1)), 0644))
+
+	m := &api.PlaygroundManifest{Playground: api.PlaygroundSpec{
+		InitTasks: map[string]api.InitTask{"install": {RunFrom: "./missing.sh"}},
+	}}
+	assert.ErrorContains(t, InlineFiles(m, dir), "init task install: file ./missing.sh not found")
+
+	m.Playground.InitTasks["install"] = api.InitTask{RunFrom: "big.sh"}
+	assert.ErrorContains(t, InlineFiles(m, dir), "too large")
+
+	m.Playground.InitTasks["install"] = api.InitTask{Run: "true", RunFrom: "big.sh"}
+	assert.ErrorContains(t, InlineFiles(m, dir), "mutually exclusive")
+}
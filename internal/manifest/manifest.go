@@ -0,0 +1,404 @@
+// Package manifest checks playground manifests client-side, so that the
+// mistakes the server would reject (or, worse, accept and fail to start
+// with) are reported before a round-trip, with the lines they are at.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+// Finding is a single problem in the manifest.
+type Finding struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s: %s", f.Line, f.Path, f.Message)
+}
+
+var sizeRegex = regexp.MustCompile(`(?i)^[0-9]+(\.[0-9]+)?\s*[kmgt]i?b?$`)
+
+// Validate parses the manifest and checks it for the mistakes that can be
+// found without asking the server. The findings are ordered by line. An
+// error is returned only if the manifest can't be parsed at all.
+//
+// References to networks and machines are only checked if the manifest
+// declares them - otherwise they come from the base playground.
+func Validate(data []byte) (*api.PlaygroundManifest, []Finding, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, errors.New("manifest is empty")
+	}
+
+	var manifest api.PlaygroundManifest
+	if err := doc.Decode(&manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	v := &validator{root: doc.Content[0], manifest: &manifest}
+	v.run()
+
+	slices.SortStableFunc(v.findings, func(a, b Finding) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+
+	return &manifest, v.findings, nil
+}
+
+type validator struct {
+	root     *yaml.Node
+	manifest *api.PlaygroundManifest
+
+	// subnets of the networks with a valid one, by network name.
+	subnets map[string]netip.Prefix
+
+	findings []Finding
+}
+
+func (v *validator) run() {
+	if v.manifest.Kind != "playground" {
+		v.report([]any{"kind"}, "invalid manifest kind %q (expected 'playground')", v.manifest.Kind)
+	}
+
+	v.checkNetworks()
+	v.checkMachines()
+	v.checkTabs()
+	v.checkInitTasks()
+}
+
+func (v *validator) checkNetworks() {
+	type namedPrefix struct {
+		name   string
+		prefix netip.Prefix
+	}
+
+	var (
+		seen  = map[string]bool{}
+		valid []namedPrefix
+	)
+
+	v.subnets = map[string]netip.Prefix{}
+
+	for i, n := range v.manifest.Playground.Networks {
+		at := []any{"playground", "networks", i}
+
+		if n.Name == "" {
+			v.report(at, "network name is required")
+		} else if seen[n.Name] {
+			v.report(append(at, "name"), "duplicate network name %q", n.Name)
+		}
+		seen[n.Name] = true
+
+		prefix, err := netip.ParsePrefix(n.Subnet)
+		if err != nil {
+			v.report(append(at, "subnet"), "invalid subnet %q (expected CIDR notation, e.g., 172.16.0.0/24)", n.Subnet)
+			continue
+		}
+		if prefix != prefix.Masked() {
+			v.report(append(at, "subnet"), "subnet %s has host bits set (did you mean %s?)", prefix, prefix.Masked())
+			prefix = prefix.Masked()
+		}
+
+		for _, other := range valid {
+			if prefix.Overlaps(other.prefix) {
+				v.report(append(at, "subnet"), "subnet %s overlaps with the subnet of network %q (%s)", prefix, other.name, other.prefix)
+			}
+		}
+
+		if n.Gateway != "" {
+			gateway, err := netip.ParseAddr(n.Gateway)
+			if err != nil {
+				v.report(append(at, "gateway"), "invalid gateway address %q", n.Gateway)
+			} else if !prefix.Contains(gateway) {
+				v.report(append(at, "gateway"), "gateway %s is outside the subnet %s", gateway, prefix)
+			}
+		}
+
+		valid = append(valid, namedPrefix{name: n.Name, prefix: prefix})
+		if _, ok := v.subnets[n.Name]; !ok {
+			v.subnets[n.Name] = prefix
+		}
+	}
+}
+
+func (v *validator) checkMachines() {
+	var (
+		seen = map[string]bool{}
+
+		// Interface addresses by network, to tell the machine they clash with.
+		addresses = map[string]map[netip.Addr]string{}
+	)
+
+	for i, m := range v.manifest.Playground.Machines {
+		at := []any{"playground", "machines", i}
+
+		if m.Name == "" {
+			v.report(at, "machine name is required")
+		} else if seen[m.Name] {
+			v.report(append(at, "name"), "duplicate machine name %q", m.Name)
+		}
+		seen[m.Name] = true
+
+		if m.Backend != "" && !api.IsValidMachineBackend(m.Backend) {
+			v.report(append(at, "backend"), "unsupported backend %q (supported: %v)", m.Backend, api.MachineBackends)
+		}
+
+		users := map[string]bool{}
+		for j, u := range m.Users {
+			if users[u.Name] {
+				v.report(append(at, "users", j, "name"), "duplicate user name %q", u.Name)
+			}
+			users[u.Name] = true
+		}
+
+		if m.Resources != nil && m.Resources.RAMSize != "" && !sizeRegex.MatchString(m.Resources.RAMSize) {
+			v.report(append(at, "resources", "ramSize"), "invalid RAM size %q (expected a number with a unit, e.g., 2Gi or 512Mi)", m.Resources.RAMSize)
+		}
+
+		mounts := map[string]int{}
+		for j, d := range m.Drives {
+			if d.Size != "" && !sizeRegex.MatchString(d.Size) {
+				v.report(append(at, "drives", j, "size"), "invalid drive size %q (expected a number with a unit, e.g., 40Gi)", d.Size)
+			}
+
+			if d.Mount == "" {
+				continue
+			}
+			if !path.IsAbs(d.Mount) {
+				v.report(append(at, "drives", j, "mount"), "mount point %q must be an absolute path", d.Mount)
+				continue
+			}
+
+			mount := path.Clean(d.Mount)
+			if other, ok := mounts[mount]; ok {
+				v.report(append(at, "drives", j, "mount"), "mount point %s is already used by drive #%d", mount, other+1)
+				continue
+			}
+			mounts[mount] = j
+		}
+
+		if m.Network == nil {
+			continue
+		}
+
+		for j, iface := range m.Network.Interfaces {
+			ifaceAt := append(at, "network", "interfaces", j)
+
+			subnet, known := v.subnets[iface.Network]
+			if iface.Network != "" && len(v.manifest.Playground.Networks) > 0 && !v.declaresNetwork(iface.Network) {
+				v.report(append(ifaceAt, "network"), "unknown network %q", iface.Network)
+			}
+
+			if iface.Address == "" {
+				continue
+			}
+
+			addr, err := parseAddress(iface.Address)
+			if err != nil {
+				v.report(append(ifaceAt, "address"), "invalid address %q", iface.Address)
+				continue
+			}
+			if !known {
+				continue
+			}
+
+			if !subnet.Contains(addr) {
+				v.report(append(ifaceAt, "address"), "address %s is outside the subnet of network %q (%s)", addr, iface.Network, subnet)
+				continue
+			}
+
+			if addresses[iface.Network] == nil {
+				addresses[iface.Network] = map[netip.Addr]string{}
+			}
+			if other, ok := addresses[iface.Network][addr]; ok {
+				v.report(append(ifaceAt, "address"), "address %s is already used by machine %q", addr, other)
+				continue
+			}
+			addresses[iface.Network][addr] = m.Name
+		}
+	}
+}
+
+func (v *validator) checkTabs() {
+	seen := map[string]bool{}
+
+	for i, t := range v.manifest.Playground.Tabs {
+		at := []any{"playground", "tabs", i}
+
+		if t.ID != "" {
+			if seen[t.ID] {
+				v.report(append(at, "id"), "duplicate tab ID %q", t.ID)
+			}
+			seen[t.ID] = true
+		}
+
+		if t.Machine != "" && !v.knowsMachine(t.Machine) {
+			v.report(append(at, "machine"), "unknown machine %q", t.Machine)
+		}
+	}
+}
+
+func (v *validator) checkInitTasks() {
+	tasks := v.manifest.Playground.InitTasks
+
+	// Tasks are referred to by their keys, but the names work too.
+	keys := map[string]string{}
+	for key, task := range tasks {
+		keys[key] = key
+		if task.Name != "" {
+			if _, ok := keys[task.Name]; !ok {
+				keys[task.Name] = key
+			}
+		}
+	}
+
+	deps := map[string][]string{}
+	for _, key := range slices.Sorted(maps.Keys(tasks)) {
+		task := tasks[key]
+		at := []any{"playground", "initTasks", key}
+
+		if task.Machine != "" && !v.knowsMachine(task.Machine) {
+			v.report(append(at, "machine"), "unknown machine %q", task.Machine)
+		}
+
+		for j, need := range task.Needs {
+			dep, ok := keys[need]
+			if !ok {
+				v.report(append(at, "needs", j), "unknown task %q", need)
+				continue
+			}
+			deps[key] = append(deps[key], dep)
+		}
+	}
+
+	// A depth-first search reporting every cycle once, at the task it's
+	// entered from.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		state = map[string]int{}
+		stack []string
+		visit func(key string)
+	)
+
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+
+		for _, dep := range deps[key] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				cycle := append(slices.Clone(stack[slices.Index(stack, dep):]), dep)
+				v.report([]any{"playground", "initTasks", dep, "needs"}, "dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(tasks)) {
+		if state[key] == unvisited {
+			visit(key)
+		}
+	}
+}
+
+func (v *validator) declaresNetwork(name string) bool {
+	return slices.ContainsFunc(v.manifest.Playground.Networks, func(n api.PlaygroundNetwork) bool {
+		return n.Name == name
+	})
+}
+
+func (v *validator) knowsMachine(name string) bool {
+	machines := v.manifest.Playground.Machines
+	return len(machines) == 0 || slices.ContainsFunc(machines, func(m api.PlaygroundMachine) bool {
+		return m.Name == name
+	})
+}
+
+// report adds a finding located at the node the path leads to (mapping keys
+// and sequence indices), or at the closest existing parent of it.
+func (v *validator) report(at []any, format string, a ...any) {
+	var (
+		node     = v.root
+		found    = true
+		segments []string
+	)
+
+	for _, seg := range at {
+		var next *yaml.Node
+
+		switch seg := seg.(type) {
+		case string:
+			if len(segments) > 0 {
+				segments = append(segments, ".")
+			}
+			segments = append(segments, seg)
+
+			if found && node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == seg {
+						next = node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			segments = append(segments, "["+strconv.Itoa(seg)+"]")
+
+			if found && node.Kind == yaml.SequenceNode && seg < len(node.Content) {
+				next = node.Content[seg]
+			}
+		}
+
+		if next == nil {
+			found = false
+		} else if found {
+			node = next
+		}
+	}
+
+	v.findings = append(v.findings, Finding{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    strings.Join(segments, ""),
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// parseAddress accepts both plain addresses and the ones with a prefix
+// length (e.g., 172.16.0.2/24).
+func parseAddress(s string) (netip.Addr, error) {
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		return prefix.Addr(), nil
+	}
+	return netip.ParseAddr(s)
+}
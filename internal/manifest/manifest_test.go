@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validManifest = `kind: playground
+name: my-k8s
+playground:
+  networks:
+    - name: local
+      subnet: 172.16.0.0/24
+    - name: backend
+      subnet: 10.0.0.0/16
+  machines:
+    - name: cplane-01
+      backend: firecracker
+      resources:
+        cpuCount: 2
+        ramSize: 4Gi
+      drives:
+        - source: ubuntu-24-04
+          mount: /
+          size: 40GiB
+      network:
+        interfaces:
+          - network: local
+            address: 172.16.0.2/24
+          - network: backend
+            address: 10.0.0.2
+    - name: node-01
+      network:
+        interfaces:
+          - network: local
+            address: 172.16.0.3
+  tabs:
+    - kind: terminal
+      name: cplane-01
+      machine: cplane-01
+  initTasks:
+    install_k8s:
+      name: install_k8s
+      machine: cplane-01
+      run: ./install.sh
+    join:
+      machine: node-01
+      needs: [install_k8s]
+      run: ./join.sh
+`
+
+func TestValidate(t *testing.T) {
+	manifest, findings, err := Validate([]byte(validManifest))
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.Equal(t, "my-k8s", manifest.Name)
+}
+
+func TestValidateFindings(t *testing.T) {
+	_, findings, err := Validate([]byte(`kind: playground
+playground:
+  networks:
+    - name: local
+      subnet: 172.16.0.0/24
+    - name: local
+      subnet: 172.16.0.128/25
+    - name: other
+      subnet: 10.0.0.1/8
+  machines:
+    - name: node-01
+      backend: qemu
+      resources:
+        ramSize: 4 gigs
+      drives:
+        - mount: /data
+        - mount: /data/
+      network:
+        interfaces:
+          - network: local
+            address: 192.168.0.2
+          - network: nowhere
+    - name: node-01
+  tabs:
+    - kind: terminal
+      machine: node-02
+  initTasks:
+    a:
+      needs: [b]
+    b:
+      needs: [c, missing]
+    c:
+      machine: node-03
+      needs: [a]
+`))
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	assert.Equal(t, []string{
+		`line 6: playground.networks[1].name: duplicate network name "local"`,
+		`line 7: playground.networks[1].subnet: subnet 172.16.0.128/25 overlaps with the subnet of network "local" (172.16.0.0/24)`,
+		`line 9: playground.networks[2].subnet: subnet 10.0.0.1/8 has host bits set (did you mean 10.0.0.0/8?)`,
+		`line 12: playground.machines[0].backend: unsupported backend "qemu" (supported: [firecracker cloud-hypervisor])`,
+		`line 14: playground.machines[0].resources.ramSize: invalid RAM size "4 gigs" (expected a number with a unit, e.g., 2Gi or 512Mi)`,
+		`line 17: playground.machines[0].drives[1].mount: mount point /data is already used by drive #1`,
+		`line 21: playground.machines[0].network.interfaces[0].address: address 192.168.0.2 is outside the subnet of network "local" (172.16.0.0/24)`,
+		`line 22: playground.machines[0].network.interfaces[1].network: unknown network "nowhere"`,
+		`line 23: playground.machines[1].name: duplicate machine name "node-01"`,
+		`line 26: playground.tabs[0].machine: unknown machine "node-02"`,
+		`line 29: playground.initTasks.a.needs: dependency cycle: a -> b -> c -> a`,
+		`line 31: playground.initTasks.b.needs[1]: unknown task "missing"`,
+		`line 33: playground.initTasks.c.machine: unknown machine "node-03"`,
+	}, got)
+}
+
+func TestValidateLocatesMissingKeys(t *testing.T) {
+	_, findings, err := Validate([]byte("kind: tutorial\nplayground:\n  networks:\n    - subnet: 172.16.0.0/24\n"))
+	require.NoError(t, err)
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{Line: 1, Column: 7, Path: "kind", Message: `invalid manifest kind "tutorial" (expected 'playground')`}, findings[0])
+
+	// No name key - pointing at the network itself.
+	assert.Equal(t, 4, findings[1].Line)
+	assert.Equal(t, "playground.networks[0]", findings[1].Path)
+
+	_, _, err = Validate([]byte("kind: [playground"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// patchKey marks the overlay items (list elements matched by name, or map
+// entries) that remove the matching base item instead of patching it:
+//
+//	machines:
+//	  - name: node-02
+//	    $patch: delete
+const patchKey = "$patch"
+
+// noValue is what text/template prints for the missing map keys.
+const noValue = "<no value>"
+
+// Template executes the manifest as a Go text/template with the values
+// available as .Values. Printing a missing value is an error, but the value
+// can still be given a default (e.g., {{ .Values.ramSize | default "2Gi" }}).
+func Template(data []byte, values map[string]any) ([]byte, error) {
+	tmpl, err := template.New("manifest").
+		Funcs(templateFuncs).
+		Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]any{"Values": values}); err != nil {
+		return nil, fmt.Errorf("failed to render manifest template: %w", err)
+	}
+
+	if line := bytes.Index(buf.Bytes(), []byte(noValue)); line != -1 {
+		return nil, fmt.Errorf("manifest template refers to a value that isn't set at line %d (use --set or --values, or give it a default)",
+			bytes.Count(buf.Bytes()[:line], []byte("\n"))+1)
+	}
+	return buf.Bytes(), nil
+}
+
+var templateFuncs = template.FuncMap{
+	// seq returns 1..n - for generating numbered machines.
+	"seq": func(n int) []int {
+		s := make([]int, 0, max(n, 0))
+		for i := 1; i <= n; i++ {
+			s = append(s, i)
+		}
+		return s
+	},
+	"default": func(def any, v any) any {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	"required": func(msg string, v any) (any, error) {
+		if v == nil || v == "" {
+			return nil, errors.New(msg)
+		}
+		return v, nil
+	},
+	"quote": func(v any) string {
+		return strconv.Quote(fmt.Sprint(v))
+	},
+}
+
+// SetValue applies a --set key=value expression to the values. Dotted keys
+// set nested values (e.g., registry.url=...), and the value is parsed as a
+// YAML scalar, so numbers and booleans keep their types.
+func SetValue(values map[string]any, expr string) error {
+	key, raw, ok := strings.Cut(expr, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid value %q (expected key=value)", expr)
+	}
+
+	var value any = raw
+	if raw != "" {
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil || isCollection(value) {
+			value = raw
+		}
+	}
+
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := values[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			values[part] = next
+		}
+		values = next
+	}
+	values[parts[len(parts)-1]] = value
+	return nil
+}
+
+// MergeValues deep-merges the values from src into dst, src taking priority.
+func MergeValues(dst, src map[string]any) {
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]any); ok {
+			if dstMap, ok := dst[k].(map[string]any); ok {
+				MergeValues(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// Overlay patches the base manifest with the overlays, in order, the way
+// kustomize's strategic merge does: maps are merged key by key (so init
+// tasks are patched by name), lists of named objects (machines, networks,
+// tabs, etc.) item by item by name, with the new items appended, and the
+// other values are replaced. The YAML nodes are merged as is, so the scalars
+// keep their original form (e.g., mode: 0755 stays 0755).
+func Overlay(base []byte, overlays ...[]byte) ([]byte, error) {
+	if len(overlays) == 0 {
+		return base, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(base, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+
+	for i, data := range overlays {
+		var patch yaml.Node
+		if err := yaml.Unmarshal(data, &patch); err != nil {
+			return nil, fmt.Errorf("failed to parse overlay #%d: %w", i+1, err)
+		}
+		if len(patch.Content) > 0 {
+			root = merge(root, patch.Content[0])
+		}
+	}
+
+	if root == nil {
+		return base, nil
+	}
+	return yaml.Marshal(root)
+}
+
+func merge(base, patch *yaml.Node) *yaml.Node {
+	switch patch.Kind {
+	case yaml.MappingNode:
+		if base == nil || base.Kind != yaml.MappingNode {
+			return withoutDirectives(patch)
+		}
+
+		for i := 0; i+1 < len(patch.Content); i += 2 {
+			key, value := patch.Content[i], patch.Content[i+1]
+			if key.Value == patchKey {
+				continue
+			}
+
+			idx := mappingIndex(base, key.Value)
+			switch {
+			case idx == -1 && !isDelete(value):
+				base.Content = append(base.Content, key, withoutDirectives(value))
+			case idx != -1 && isDelete(value):
+				base.Content = append(base.Content[:idx], base.Content[idx+2:]...)
+			case idx != -1:
+				base.Content[idx+1] = merge(base.Content[idx+1], value)
+			}
+		}
+		return base
+
+	case yaml.SequenceNode:
+		if base == nil || base.Kind != yaml.SequenceNode || !allNamed(patch) || !allNamed(base) {
+			return withoutDirectives(patch)
+		}
+
+		for _, item := range patch.Content {
+			name := itemName(item)
+
+			idx := -1
+			for i, baseItem := range base.Content {
+				if itemName(baseItem) == name {
+					idx = i
+					break
+				}
+			}
+
+			switch {
+			case idx == -1 && !isDelete(item):
+				base.Content = append(base.Content, withoutDirectives(item))
+			case idx != -1 && isDelete(item):
+				base.Content = append(base.Content[:idx], base.Content[idx+1:]...)
+			case idx != -1:
+				base.Content[idx] = merge(base.Content[idx], item)
+			}
+		}
+		return base
+	}
+
+	return patch
+}
+
+// mappingIndex returns the index of the key in the mapping node's content
+// (the value follows it), or -1.
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func allNamed(list *yaml.Node) bool {
+	for _, item := range list.Content {
+		if itemName(item) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func itemName(item *yaml.Node) string {
+	if item.Kind != yaml.MappingNode {
+		return ""
+	}
+	if idx := mappingIndex(item, "name"); idx != -1 && item.Content[idx+1].Kind == yaml.ScalarNode {
+		return item.Content[idx+1].Value
+	}
+	return ""
+}
+
+func isDelete(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	idx := mappingIndex(node, patchKey)
+	return idx != -1 && node.Content[idx+1].Value == "delete"
+}
+
+// withoutDirectives strips the $patch keys from the overlay's new items and
+// drops the ones marked for deletion - there is nothing to delete them from.
+func withoutDirectives(node *yaml.Node) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == patchKey || isDelete(value) {
+				continue
+			}
+			content = append(content, key, withoutDirectives(value))
+		}
+		node.Content = content
+
+	case yaml.SequenceNode:
+		var content []*yaml.Node
+		for _, item := range node.Content {
+			if !isDelete(item) {
+				content = append(content, withoutDirectives(item))
+			}
+		}
+		node.Content = content
+	}
+	return node
+}
+
+func isCollection(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
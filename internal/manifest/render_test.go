@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func TestTemplate(t *testing.T) {
+	values := map[string]any{}
+	MergeValues(values, map[string]any{"nodes": 1, "registry": map[string]any{"url": "registry.local", "user": "me"}})
+	require.NoError(t, SetValue(values, "nodes=2"))
+	require.NoError(t, SetValue(values, "registry.url=ghcr.io"))
+	require.NoError(t, SetValue(values, "empty="))
+
+	assert.Equal(t, map[string]any{
+		"nodes":    2,
+		"registry": map[string]any{"url": "ghcr.io", "user": "me"},
+		"empty":    "",
+	}, values)
+
+	assert.Error(t, SetValue(values, "nodes"))
+
+	data, err := Template([]byte(`kind: playground
+playground:
+  registryAuth: {{ .Values.registry.url }}
+  machines:
+  {{- range $i := seq .Values.nodes }}
+    - name: node-{{ printf "%02d" $i }}
+      resources:
+        ramSize: {{ $.Values.ramSize | default "2Gi" | quote }}
+  {{- end }}
+`), values)
+	require.NoError(t, err)
+
+	var m api.PlaygroundManifest
+	require.NoError(t, yaml.Unmarshal(data, &m))
+	assert.Equal(t, "ghcr.io", m.Playground.RegistryAuth)
+	require.Len(t, m.Playground.Machines, 2)
+	assert.Equal(t, "node-02", m.Playground.Machines[1].Name)
+	assert.Equal(t, "2Gi", m.Playground.Machines[1].Resources.RAMSize)
+
+	_, err = Template([]byte("kind: playground\nname: {{ .Values.missing }}\n"), values)
+	assert.ErrorContains(t, err, "isn't set at line 2")
+}
+
+func TestOverlay(t *testing.T) {
+	base := []byte(`kind: playground
+title: Dev
+playground:
+  machines:
+    - name: node-01
+      resources:
+        cpuCount: 2
+        ramSize: 2Gi
+    - name: node-02
+  initTasks:
+    install:
+      machine: node-01
+      run: ./install.sh
+    debug:
+      run: ./debug.sh
+`)
+
+	data, err := Overlay(base, []byte(`title: Prod
+playground:
+  machines:
+    - name: node-01
+      resources:
+        ramSize: 8Gi
+    - name: node-02
+      $patch: delete
+    - name: node-03
+  initTasks:
+    install:
+      run: ./install.sh --prod
+    debug:
+      $patch: delete
+`))
+	require.NoError(t, err)
+
+	var m api.PlaygroundManifest
+	require.NoError(t, yaml.Unmarshal(data, &m))
+
+	assert.Equal(t, "Prod", m.Title)
+	require.Len(t, m.Playground.Machines, 2)
+	assert.Equal(t, "node-01", m.Playground.Machines[0].Name)
+	assert.Equal(t, &api.MachineResources{CPUCount: 2, RAMSize: "8Gi"}, m.Playground.Machines[0].Resources)
+	assert.Equal(t, "node-03", m.Playground.Machines[1].Name)
+
+	require.Len(t, m.Playground.InitTasks, 1)
+	assert.Equal(t, api.InitTask{Machine: "node-01", Run: "./install.sh --prod"}, m.Playground.InitTasks["install"])
+
+	// No overlays - the manifest is used as is.
+	data, err = Overlay(base)
+	require.NoError(t, err)
+	assert.Equal(t, base, data)
+}
+
+func TestOverlayKeepsScalars(t *testing.T) {
+	base := []byte(`kind: playground
+playground:
+  machines:
+    - name: node-01
+      startupFiles:
+        - path: /etc/motd
+          content: hello
+          mode: 0755
+  initConditions:
+    values:
+      - key: version
+        default: 1.30
+`)
+
+	data, err := Overlay(base, []byte(`playground:
+  machines:
+    - name: node-02
+      startupFiles:
+        - path: /etc/issue
+          content: hi
+          mode: 0600
+`))
+	require.NoError(t, err)
+
+	var m api.PlaygroundManifest
+	require.NoError(t, yaml.Unmarshal(data, &m))
+
+	require.Len(t, m.Playground.Machines, 2)
+	assert.Equal(t, "0755", m.Playground.Machines[0].StartupFiles[0].Mode)
+	assert.Equal(t, "0600", m.Playground.Machines[1].StartupFiles[0].Mode)
+	assert.Equal(t, "1.30", m.Playground.InitConditions.Values[0].Default)
+}
+
+func TestOverlayDeleteWithoutMatch(t *testing.T) {
+	base := []byte(`kind: playground
+playground:
+  machines:
+    - name: node-01
+`)
+
+	data, err := Overlay(base, []byte(`playground:
+  tabs:
+    - name: gone
+      $patch: delete
+    - name: term
+      machine: node-01
+  initTasks:
+    debug:
+      $patch: delete
+    install:
+      run: ./install.sh
+`))
+	require.NoError(t, err)
+
+	var m api.PlaygroundManifest
+	require.NoError(t, yaml.Unmarshal(data, &m))
+
+	require.Len(t, m.Playground.Tabs, 1)
+	assert.Equal(t, "term", m.Playground.Tabs[0].Name)
+	assert.Equal(t, map[string]api.InitTask{"install": {Run: "./install.sh"}}, m.Playground.InitTasks)
+	assert.NotContains(t, string(data), patchKey)
+}
@@ -0,0 +1,88 @@
+package playref
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+const aliasesFileName = "aliases.yaml"
+
+// Aliases map local alias names to play IDs. They are kept in the plays
+// directory, so every config context gets its own set.
+type Aliases map[string]string
+
+func AliasesPath(playsDir string) string {
+	return filepath.Join(playsDir, aliasesFileName)
+}
+
+func LoadAliases(path string) (Aliases, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Aliases{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read aliases file: %w", err)
+	}
+
+	aliases := Aliases{}
+	if err := yaml.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("unable to decode aliases file %s: %w", path, err)
+	}
+
+	return aliases, nil
+}
+
+func (a Aliases) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("unable to create aliases directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("unable to write aliases file: %w", err)
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("unable to rename aliases file: %w", err)
+	}
+
+	return nil
+}
+
+// Names returns the aliases of the given play, sorted.
+func (a Aliases) Names(playID string) []string {
+	var names []string
+	for name, id := range a {
+		if id == playID {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+var validAliasRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateAlias rejects names that would be ambiguous on the command line
+// (e.g., 'labctl cp <alias>:<path> ...') or shadow play IDs.
+func ValidateAlias(name string) error {
+	if !validAliasRegex.MatchString(name) {
+		return fmt.Errorf("invalid alias %q: only letters, digits, '.', '_', and '-' are allowed", name)
+	}
+	if api.LooksLikePlayID(name) {
+		return fmt.Errorf("invalid alias %q: it looks like a playground ID", name)
+	}
+	return nil
+}
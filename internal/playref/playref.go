@@ -0,0 +1,144 @@
+// Package playref resolves the human-friendly ways to refer to a play - local
+// aliases, titles, ID prefixes, and playground names - to play IDs.
+package playref
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/internal/labcli"
+)
+
+// Resolve turns a play reference into a play ID. The reference can be (in
+// the order of precedence):
+//
+//   - a full play ID
+//   - a local alias (see 'labctl playground alias')
+//   - a play title
+//   - a unique play ID prefix
+//   - a playground name if exactly one play of it is active
+//   - a unique play title prefix
+func Resolve(ctx context.Context, cli labcli.CLI, ref string) (string, error) {
+	if ref == "" {
+		return "", labcli.NewStatusError(1, "playground ID, alias, or title is required")
+	}
+
+	if api.LooksLikePlayID(ref) {
+		return ref, nil
+	}
+
+	aliases, err := LoadAliases(AliasesPath(cli.Config().PlaysDir))
+	if err != nil {
+		return "", err
+	}
+	if id, ok := aliases[ref]; ok {
+		return id, nil
+	}
+
+	plays, err := List(ctx, cli.Client())
+	if err != nil {
+		return "", err
+	}
+
+	return Match(ref, plays)
+}
+
+// Match finds the play the (non-ID, non-alias) reference points to. Destroyed
+// plays are never matched.
+func Match(ref string, plays []*api.Play) (string, error) {
+	var candidates []*api.Play
+	for _, p := range plays {
+		if !p.StateIs(api.StateDestroyed) {
+			candidates = append(candidates, p)
+		}
+	}
+
+	matchers := []struct {
+		kind  string
+		match func(*api.Play) bool
+	}{
+		{"title", func(p *api.Play) bool {
+			return p.Title != "" && p.Title == ref
+		}},
+		{"ID prefix", func(p *api.Play) bool {
+			return strings.HasPrefix(p.ID, ref)
+		}},
+		{"playground name", func(p *api.Play) bool {
+			return p.Playground.Name == ref && p.IsActive()
+		}},
+		{"title prefix", func(p *api.Play) bool {
+			return p.Title != "" && strings.HasPrefix(p.Title, ref)
+		}},
+	}
+
+	for _, m := range matchers {
+		var matches []*api.Play
+		for _, p := range candidates {
+			if m.match(p) {
+				matches = append(matches, p)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0].ID, nil
+		default:
+			return "", ambiguousError(ref, m.kind, matches)
+		}
+	}
+
+	return "", labcli.NewStatusError(1,
+		"no playground matches %q - expected a playground ID, alias, title, unique ID prefix, or the name of an active playground",
+		ref)
+}
+
+func ambiguousError(ref string, kind string, matches []*api.Play) error {
+	var b strings.Builder
+	for _, p := range matches {
+		fmt.Fprintf(&b, "  %s  %s (%s)", p.ID, p.Playground.Name, p.State())
+		if p.Title != "" {
+			fmt.Fprintf(&b, "  %q", p.Title)
+		}
+		b.WriteString("\n")
+	}
+
+	return labcli.NewStatusError(1,
+		"%q is ambiguous - it matches %d playgrounds by %s:\n%s\nUse the full ID or set an alias with 'labctl playground alias <alias> <playground-id>'",
+		ref, len(matches), kind, b.String())
+}
+
+// List merges the recent plays with the persistent (possibly stopped) ones,
+// the same way 'labctl playground list' does, most recently updated first.
+func List(ctx context.Context, client *api.Client) ([]*api.Play, error) {
+	plays, err := client.ListPlays(ctx, api.ListPlaysQueryParams{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list playgrounds: %w", err)
+	}
+
+	persistent, err := client.ListPlays(ctx, api.ListPlaysQueryParams{Persistent: true})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list persistent playgrounds: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, p := range plays {
+		seen[p.ID] = true
+	}
+	for _, p := range persistent {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			plays = append(plays, p)
+		}
+	}
+
+	slices.SortFunc(plays, func(a, b *api.Play) int {
+		return strings.Compare(b.UpdatedAt, a.UpdatedAt)
+	})
+
+	return plays, nil
+}
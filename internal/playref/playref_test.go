@@ -0,0 +1,88 @@
+package playref
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func testPlay(id, playground, title string, state api.PlayState) *api.Play {
+	p := &api.Play{
+		ID:     id,
+		Title:  title,
+		Status: &api.PlayStatus{StateEvents: []api.StateEvent{{State: state}}},
+	}
+	p.Playground.Name = playground
+	return p
+}
+
+func TestMatch(t *testing.T) {
+	plays := []*api.Play{
+		testPlay("65e78a64366c2b0cf9ddc34c", "k3s", "myk3s", api.StateRunning),
+		testPlay("65e78a64366c2b0cf9ddc34d", "docker", "docker-dev", api.StateRunning),
+		testPlay("66aaaa64366c2b0cf9ddc34e", "docker", "docker-old", api.StateStopped),
+		testPlay("67bbbb64366c2b0cf9ddc34f", "ubuntu", "gone", api.StateDestroyed),
+		testPlay("67cccc64366c2b0cf9ddc350", "ubuntu", "", api.StateRunning),
+		testPlay("67dddd64366c2b0cf9ddc351", "ubuntu", "", api.StateRunning),
+	}
+
+	for ref, want := range map[string]string{
+		"myk3s":      "65e78a64366c2b0cf9ddc34c", // title
+		"docker-old": "66aaaa64366c2b0cf9ddc34e", // title of a stopped play
+		"66aa":       "66aaaa64366c2b0cf9ddc34e", // ID prefix
+		"my":         "65e78a64366c2b0cf9ddc34c", // title prefix
+		"k3s":        "65e78a64366c2b0cf9ddc34c", // playground name
+		"docker":     "65e78a64366c2b0cf9ddc34d", // the only active docker play
+	} {
+		got, err := Match(ref, plays)
+		if assert.NoError(t, err, ref) {
+			assert.Equal(t, want, got, ref)
+		}
+	}
+
+	// Ambiguous ID prefix.
+	_, err := Match("65e7", plays)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+	assert.Contains(t, err.Error(), "65e78a64366c2b0cf9ddc34c")
+	assert.Contains(t, err.Error(), "65e78a64366c2b0cf9ddc34d")
+
+	// Two active plays of the same playground.
+	_, err = Match("ubuntu", plays)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+
+	// Destroyed plays are never matched.
+	_, err = Match("gone", plays)
+	assert.Error(t, err)
+
+	_, err = Match("nope", plays)
+	assert.Error(t, err)
+}
+
+func TestAliases(t *testing.T) {
+	path := AliasesPath(filepath.Join(t.TempDir(), "plays"))
+
+	aliases, err := LoadAliases(path)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	aliases["dev"] = "65e78a64366c2b0cf9ddc34c"
+	aliases["k8s"] = "65e78a64366c2b0cf9ddc34c"
+	aliases["other"] = "65e78a64366c2b0cf9ddc34d"
+	require.NoError(t, aliases.Save(path))
+
+	loaded, err := LoadAliases(path)
+	require.NoError(t, err)
+	assert.Equal(t, aliases, loaded)
+	assert.Equal(t, []string{"dev", "k8s"}, loaded.Names("65e78a64366c2b0cf9ddc34c"))
+
+	assert.NoError(t, ValidateAlias("my-k3s_1.2"))
+	assert.Error(t, ValidateAlias("my:k3s"))
+	assert.Error(t, ValidateAlias("-flag"))
+	assert.Error(t, ValidateAlias("65e78a64366c2b0cf9ddc34c"))
+}
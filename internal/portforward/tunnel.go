@@ -101,7 +101,7 @@ func StartTunnel(ctx context.Context, client *api.Client, opts TunnelOptions) (*
 	}
 
 	token, err := backoff.Retry(ctx, func() (string, error) {
-		return authenticate(ctx, resp.LoginURL, conductorSessionCookieName)
+		return authenticate(ctx, client.HTTPClient(), resp.LoginURL, conductorSessionCookieName)
 	}, retryOptions(authenticateTimeout)...)
 	if err != nil {
 		return nil, fmt.Errorf("authenticate(): %w", err)
@@ -162,15 +162,10 @@ func (o TunnelOptions) reportProgress(ctx context.Context) (stop func() (printed
 // in front of it timed out, or we're being rate limited. Everything else - a
 // play that's gone, a machine whose agents have had their full budget and aren't
 // coming up, a rejected request - is the server's final answer, and the client
-// must stop on it. The api package classifies these once, but flattens them
-// back to plain errors on the way out, so the decision has to be re-made here.
+// must stop on it. The api package classifies these once, in APIError.
 func retryableTunnelError(err error) bool {
-	var retryAfter *backoff.RetryAfterError
-
-	return errors.Is(err, api.ErrServiceUnavailable) ||
-		errors.Is(err, api.ErrGatewayTimeout) ||
-		errors.Is(err, api.ErrRateLimitExceeded) ||
-		errors.As(err, &retryAfter)
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
 }
 
 // retryOptions is the single retry policy behind tunnel setup. The server
@@ -288,13 +283,16 @@ func drainForwardingErrors(ctx context.Context, errCh <-chan error) {
 	}
 }
 
-func authenticate(ctx context.Context, url string, name string) (string, error) {
+// authenticate exchanges a tunnel login URL for the conductor session cookie.
+// It goes through the API client's HTTP client so that the same proxy, CAs and
+// timeouts apply, but the request itself carries no API credentials.
+func authenticate(ctx context.Context, httpClient *http.Client, url string, name string) (string, error) {
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
 
-	httpResp, err := http.DefaultClient.Do(httpReq)
+	httpResp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return "", err
 	}
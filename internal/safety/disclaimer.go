@@ -1,6 +1,9 @@
 package safety
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/iximiuz/labctl/internal/labcli"
 )
 
@@ -23,3 +26,32 @@ func ShowSafetyDisclaimer(cli labcli.CLI) (bool, error) {
 
 	return false, labcli.NewStatusError(0, "See you later!")
 }
+
+// ShowPlaygroundDisclaimerIfNeeded asks for consent before starting a
+// third-party playground, unless it's been given already (consent) or the
+// playground is official or owned by the current user.
+func ShowPlaygroundDisclaimerIfNeeded(ctx context.Context, playgroundName string, cli labcli.CLI, consent bool) (bool, error) {
+	if consent {
+		return true, nil
+	}
+
+	playground, err := cli.Client().GetPlayground(ctx, playgroundName, nil)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get the playground: %w", err)
+	}
+
+	if playground.Owner == "" { // official playgrounds don't need consent
+		return true, nil
+	}
+
+	me, err := cli.Client().GetMe(ctx)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get the current user info: %w", err)
+	}
+
+	if me.ID == playground.Owner {
+		return true, nil
+	}
+
+	return ShowSafetyDisclaimer(cli)
+}
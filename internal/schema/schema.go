@@ -0,0 +1,245 @@
+// Package schema generates JSON Schemas of the YAML files authors edit by
+// hand - playground manifests and content front matter - from the API types,
+// so that editors (via yaml-language-server) can offer completion and inline
+// errors.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/iximiuz/labctl/api"
+	"github.com/iximiuz/labctl/content"
+)
+
+// Draft-07 is the newest draft yaml-language-server fully supports.
+const draft = "http://json-schema.org/draft-07/schema#"
+
+// ModelineKey is what yaml-language-server looks for in a YAML comment to
+// pick the schema of the file.
+const ModelineKey = "yaml-language-server: $schema="
+
+type Schema map[string]any
+
+// frontMatter is the part of the content (index.md) front matter labctl
+// knows about. The rest of it is allowed but not described.
+type frontMatter struct {
+	Kind        content.ContentKind   `yaml:"kind"`
+	Title       string                `yaml:"title"`
+	Description string                `yaml:"description"`
+	Categories  []string              `yaml:"categories"`
+	Tagz        []string              `yaml:"tagz"`
+	Cover       string                `yaml:"cover"`
+	CreatedAt   string                `yaml:"createdAt"`
+	UpdatedAt   string                `yaml:"updatedAt"`
+	Playground  frontMatterPlayground `yaml:"playground"`
+}
+
+type frontMatterPlayground struct {
+	Name string `yaml:"name"`
+
+	api.PlaygroundSpec `yaml:",inline"`
+}
+
+// PlaygroundManifest returns the schema of the playground manifest files
+// ('labctl playground create/update --file').
+func PlaygroundManifest() ([]byte, error) {
+	g := newGenerator()
+
+	root := g.object(reflect.TypeFor[api.PlaygroundManifest]())
+	root["properties"].(Schema)["kind"] = Schema{"const": "playground"}
+	root["required"] = []string{"kind", "playground"}
+
+	return g.document(root, "labctl playground manifest")
+}
+
+// ContentFrontMatter returns the schema of the front matter of the content
+// markdown files (challenges, tutorials, courses, etc.).
+func ContentFrontMatter() ([]byte, error) {
+	g := newGenerator()
+
+	root := g.object(reflect.TypeFor[frontMatter]())
+	root["additionalProperties"] = true
+
+	return g.document(root, "labctl content front matter")
+}
+
+type generator struct {
+	defs Schema
+}
+
+func newGenerator() *generator {
+	return &generator{defs: Schema{}}
+}
+
+func (g *generator) document(root Schema, title string) ([]byte, error) {
+	root["$schema"] = draft
+	root["title"] = title
+	root["definitions"] = g.defs
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *generator) schemaOf(t reflect.Type) Schema {
+	switch t {
+	case reflect.TypeFor[api.MachineBackend]():
+		return Schema{"type": "string", "enum": api.MachineBackends}
+
+	case reflect.TypeFor[content.ContentKind]():
+		return Schema{"type": "string", "enum": []content.ContentKind{
+			content.KindChallenge,
+			content.KindCourse,
+			content.KindRoadmap,
+			content.KindSkillPath,
+			content.KindTutorial,
+			content.KindTraining,
+			content.KindPlayground,
+			content.KindVendor,
+			content.KindBlogPost,
+		}}
+
+	case reflect.TypeFor[api.MachineKernel]():
+		// See api.PlaygroundMachine.UnmarshalYAML.
+		return Schema{"anyOf": []Schema{
+			{"type": "string", "description": "Kernel source (legacy form)"},
+			g.ref(t),
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+
+	return Schema{}
+}
+
+// ref adds the struct to the definitions (once) and refers to it.
+func (g *generator) ref(t reflect.Type) Schema {
+	if _, ok := g.defs[t.Name()]; !ok {
+		g.defs[t.Name()] = Schema{} // a placeholder for recursive types
+		g.defs[t.Name()] = g.object(t)
+	}
+	return Schema{"$ref": "#/definitions/" + t.Name()}
+}
+
+func (g *generator) object(t reflect.Type) Schema {
+	props := Schema{}
+	g.addProperties(t, props)
+
+	return Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+// addProperties follows the yaml.v3 field naming: the tag name if any, the
+// lowercased field name otherwise, with inlined structs flattened.
+func (g *generator) addProperties(t reflect.Type, props Schema) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if opts == "inline" {
+			g.addProperties(f.Type, props)
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		s := g.schemaOf(f.Type)
+		if t == reflect.TypeFor[api.PlaygroundSpec]() && f.Name == "Access" {
+			s["description"] = "Deprecated: use accessControl instead"
+		}
+		props[name] = s
+	}
+}
+
+// AddModeline points yaml-language-server at the schema: in the front matter
+// of a markdown file or at the top of a YAML file. Files that already have a
+// modeline, and markdown files without front matter, are returned as is.
+func AddModeline(data []byte, markdown bool, schemaURL string) ([]byte, bool) {
+	if bytes.Contains(data, []byte(ModelineKey)) {
+		return data, false
+	}
+
+	modeline := []byte("# " + ModelineKey + schemaURL + "\n")
+
+	if !markdown {
+		return append(modeline, data...), true
+	}
+
+	for _, opening := range []string{"---\n", "---\r\n"} {
+		if rest, ok := bytes.CutPrefix(data, []byte(opening)); ok {
+			return bytes.Join([][]byte{[]byte(opening), modeline, rest}, nil), true
+		}
+	}
+	return data, false
+}
+
+const (
+	PlaygroundManifestFile = "playground-manifest.schema.json"
+	ContentFrontMatterFile = "content-front-matter.schema.json"
+)
+
+// Install writes the schemas to the directory, so that the modelines can
+// refer to them, and returns the path of the requested one. The files are
+// rewritten every time to keep up with the labctl version.
+func Install(dir string, file string) (string, error) {
+	generate := PlaygroundManifest
+	if file == ContentFrontMatterFile {
+		generate = ContentFrontMatter
+	}
+
+	data, err := generate()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create schemas directory: %w", err)
+	}
+
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("unable to write schema: %w", err)
+	}
+	return path, nil
+}
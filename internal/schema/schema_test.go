@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	var s map[string]any
+	require.NoError(t, json.Unmarshal(data, &s))
+	return s
+}
+
+func TestPlaygroundManifest(t *testing.T) {
+	data, err := PlaygroundManifest()
+	require.NoError(t, err)
+	s := decode(t, data)
+
+	assert.Equal(t, []any{"kind", "playground"}, s["required"])
+	assert.Equal(t, map[string]any{"$ref": "#/definitions/PlaygroundSpec"}, s["properties"].(map[string]any)["playground"])
+
+	defs := s["definitions"].(map[string]any)
+
+	machine := defs["PlaygroundMachine"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"firecracker", "cloud-hypervisor"}}, machine["backend"])
+
+	// Both the legacy string and the current object forms of the kernel.
+	assert.Equal(t, map[string]any{"anyOf": []any{
+		map[string]any{"type": "string", "description": "Kernel source (legacy form)"},
+		map[string]any{"$ref": "#/definitions/MachineKernel"},
+	}}, machine["kernel"])
+
+	// No YAML tags - the lowercased field names.
+	startupFile := defs["MachineStartupFile"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, startupFile, "path")
+	assert.Contains(t, startupFile, "append")
+}
+
+func TestContentFrontMatter(t *testing.T) {
+	data, err := ContentFrontMatter()
+	require.NoError(t, err)
+	s := decode(t, data)
+
+	assert.Equal(t, true, s["additionalProperties"])
+
+	// The playground's name and its inlined spec.
+	playground := s["definitions"].(map[string]any)["frontMatterPlayground"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, playground, "name")
+	assert.Contains(t, playground, "machines")
+	assert.Contains(t, playground, "tabs")
+}
+
+func TestAddModeline(t *testing.T) {
+	const modeline = "# yaml-language-server: $schema=/schemas/s.json\n"
+
+	data, ok := AddModeline([]byte("---\ntitle: Hello\n---\n\n# Hello\n"), true, "/schemas/s.json")
+	assert.True(t, ok)
+	assert.Equal(t, "---\n"+modeline+"title: Hello\n---\n\n# Hello\n", string(data))
+
+	// Already there.
+	_, ok = AddModeline(data, true, "/schemas/other.json")
+	assert.False(t, ok)
+
+	// No front matter to put it in.
+	_, ok = AddModeline([]byte("# Hello\n"), true, "/schemas/s.json")
+	assert.False(t, ok)
+
+	data, ok = AddModeline([]byte("kind: playground\n"), false, "/schemas/s.json")
+	assert.True(t, ok)
+	assert.Equal(t, modeline+"kind: playground\n", string(data))
+}
+
+func TestInstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "schemas")
+
+	path, err := Install(dir, ContentFrontMatterFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ContentFrontMatterFile), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "labctl content front matter", decode(t, data)["title"])
+}
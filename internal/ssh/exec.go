@@ -0,0 +1,57 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Exec runs a command non-interactively: no PTY and no stdin, with the
+// command's stdout and stderr captured instead of written to the terminal.
+// It returns once the command exits (a non-zero exit status is an error) or
+// the context is cancelled.
+func (s *Session) Exec(ctx context.Context, cmd string) ([]byte, error) {
+	sess, err := s.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("create SSH session: %w", err)
+	}
+	defer sess.Close()
+
+	// stdout and stderr are copied by separate goroutines.
+	var out lockedBuffer
+	sess.Stdout = &out
+	sess.Stderr = &out
+
+	if err := sess.Start(cmd); err != nil {
+		return nil, fmt.Errorf("start command: %w", err)
+	}
+
+	waitC := make(chan error, 1)
+	go func() {
+		waitC <- sess.Wait()
+	}()
+
+	select {
+	case err := <-waitC:
+		return out.buf.Bytes(), err
+
+	case <-ctx.Done():
+		// Closing the session unblocks Wait, so the goroutine above
+		// doesn't outlive the call.
+		sess.Close()
+		<-waitC
+		return nil, ctx.Err()
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
@@ -0,0 +1,158 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// testServer is an SSH server that runs "true" and "false" (printing the
+// command) and records the requests that would touch the user's terminal.
+type testServer struct {
+	ptyRequests atomic.Int32
+	stdinBytes  atomic.Int64
+}
+
+// dial starts the server on a loopback listener (with net.Pipe, the SSH
+// version exchange would deadlock) and connects to it.
+func (s *testServer) dial(t *testing.T) net.Conn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		_, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+
+		for newCh := range chans {
+			ch, requests, err := newCh.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(ch, requests)
+		}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	return conn
+}
+
+func (s *testServer) handle(ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+
+	go func() {
+		n, _ := io.Copy(io.Discard, ch)
+		s.stdinBytes.Add(n)
+	}()
+
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			s.ptyRequests.Add(1)
+			req.Reply(false, nil)
+
+		case "exec":
+			var payload struct{ Command string }
+			ssh.Unmarshal(req.Payload, &payload)
+			req.Reply(true, nil)
+
+			status := uint32(0)
+			if payload.Command != "true" {
+				status = 1
+			}
+			io.WriteString(ch, payload.Command+"\n")
+			ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			return
+
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+func TestExecPolls(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Anything written to the terminal would end up here.
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	require.NoError(t, err)
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stdout
+	defer func() { os.Stdout, os.Stderr = origStdout, origStderr }()
+
+	var server testServer
+	sess, err := NewSession(server.dial(t), "laborant", filepath.Join(t.TempDir(), "no-key"), false)
+	require.NoError(t, err)
+	defer sess.Close()
+
+	// The first command lets the server finish setting up the connection.
+	_, err = sess.Exec(context.Background(), "true")
+	require.NoError(t, err)
+	baseline := runtime.NumGoroutine()
+
+	for i := 0; i < 5; i++ {
+		out, err := sess.Exec(context.Background(), "false")
+		assert.Error(t, err)
+		assert.Equal(t, "false\n", string(out))
+	}
+
+	out, err := sess.Exec(context.Background(), "true")
+	require.NoError(t, err)
+	assert.Equal(t, "true\n", string(out))
+
+	// Nothing is left reading stdin or watching the terminal after the polls.
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= baseline
+	}, 5*time.Second, 10*time.Millisecond, "goroutines leaked: %d > %d", runtime.NumGoroutine(), baseline)
+
+	assert.Zero(t, server.ptyRequests.Load())
+	assert.Zero(t, server.stdinBytes.Load())
+
+	written, err := os.ReadFile(stdout.Name())
+	require.NoError(t, err)
+	assert.Empty(t, string(written))
+}
+
+func TestExecCancel(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	var server testServer
+	sess, err := NewSession(server.dial(t), "laborant", filepath.Join(t.TempDir(), "no-key"), false)
+	require.NoError(t, err)
+	defer sess.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = sess.Exec(ctx, "true")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,447 @@
+// Package taskgraph renders the dependency graph of the playground tasks
+// (formed by their needs) as Graphviz DOT, Mermaid, or plain text.
+package taskgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+type Task struct {
+	Name    string
+	Machine string
+	Needs   []string
+
+	// Status is only known for the tasks of a running playground.
+	Status    api.PlayTaskStatus
+	HasStatus bool
+
+	// Duration is the last run's duration (zero when unknown).
+	Duration time.Duration
+}
+
+type Graph struct {
+	tasks  []Task
+	byName map[string]int
+
+	// order is the topological order of the tasks (indices into tasks).
+	order []int
+	// levels[i] is the length of the longest chain of needs of tasks[i].
+	levels []int
+	// critical is the set of the tasks on the critical path.
+	critical map[string]bool
+	path     []string
+}
+
+// FromManifest builds the graph of the manifest's init tasks.
+func FromManifest(tasks map[string]api.InitTask) (*Graph, error) {
+	var list []Task
+	for name, task := range tasks {
+		list = append(list, Task{
+			Name:    name,
+			Machine: task.Machine,
+			Needs:   task.Needs,
+		})
+	}
+	return New(list)
+}
+
+// FromPlayTasks builds the graph of a running playground's tasks, with their
+// statuses and the last run's durations.
+func FromPlayTasks(tasks map[string]api.PlayTaskDetails) (*Graph, error) {
+	var list []Task
+	for name, task := range tasks {
+		list = append(list, Task{
+			Name:      name,
+			Machine:   task.Machine,
+			Needs:     task.Needs,
+			Status:    task.Status,
+			HasStatus: true,
+			Duration:  time.Duration(task.LastDurationMs) * time.Millisecond,
+		})
+	}
+	return New(list)
+}
+
+// New builds the graph of the tasks. The needs referring to unknown tasks
+// (e.g., filtered out ones) are ignored.
+func New(tasks []Task) (*Graph, error) {
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+
+	g := &Graph{
+		tasks:    tasks,
+		byName:   make(map[string]int, len(tasks)),
+		levels:   make([]int, len(tasks)),
+		critical: map[string]bool{},
+	}
+	for i, task := range tasks {
+		g.byName[task.Name] = i
+	}
+
+	// Kahn's algorithm - picking the tasks by name to keep the order stable.
+	pending := make([]int, len(tasks))
+	for i := range tasks {
+		pending[i] = len(g.needs(i))
+	}
+
+	var ready []int
+	for i := range tasks {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		g.order = append(g.order, i)
+
+		for j := range tasks {
+			for _, need := range g.needs(j) {
+				if need == i {
+					if pending[j]--; pending[j] == 0 {
+						ready = append(ready, j)
+					}
+				}
+			}
+		}
+	}
+
+	if len(g.order) < len(tasks) {
+		var cycle []string
+		for i, n := range pending {
+			if n > 0 {
+				cycle = append(cycle, tasks[i].Name)
+			}
+		}
+		return nil, fmt.Errorf("tasks %s form a dependency cycle", strings.Join(cycle, ", "))
+	}
+
+	g.computeCriticalPath()
+
+	return g, nil
+}
+
+// needs returns the indices of the known tasks the i-th task needs.
+func (g *Graph) needs(i int) []int {
+	var needs []int
+	seen := map[int]bool{}
+	for _, name := range g.tasks[i].Needs {
+		if j, ok := g.byName[name]; ok && !seen[j] {
+			seen[j] = true
+			needs = append(needs, j)
+		}
+	}
+	return needs
+}
+
+// computeCriticalPath finds the chain of needs with the longest total
+// duration - the one that determines how long the playground takes to
+// initialize. Without the durations, there is no critical path.
+func (g *Graph) computeCriticalPath() {
+	total := make([]time.Duration, len(g.tasks))
+	prev := make([]int, len(g.tasks))
+
+	end := -1
+	for _, i := range g.order {
+		prev[i] = -1
+		for _, j := range g.needs(i) {
+			if g.levels[j]+1 > g.levels[i] {
+				g.levels[i] = g.levels[j] + 1
+			}
+			if prev[i] == -1 || total[j] > total[prev[i]] {
+				prev[i] = j
+			}
+		}
+
+		total[i] = g.tasks[i].Duration
+		if prev[i] != -1 {
+			total[i] += total[prev[i]]
+		}
+
+		if end == -1 || total[i] > total[end] {
+			end = i
+		}
+	}
+
+	if end == -1 || total[end] == 0 {
+		return
+	}
+
+	for i := end; i != -1; i = prev[i] {
+		g.path = append([]string{g.tasks[i].Name}, g.path...)
+		g.critical[g.tasks[i].Name] = true
+	}
+}
+
+// CriticalPath returns the names of the tasks on the critical path (in the
+// order they run) and its total duration.
+func (g *Graph) CriticalPath() ([]string, time.Duration) {
+	var total time.Duration
+	for _, name := range g.path {
+		total += g.tasks[g.byName[name]].Duration
+	}
+	return g.path, total
+}
+
+func (g *Graph) isCriticalEdge(from, to int) bool {
+	for k := 0; k+1 < len(g.path); k++ {
+		if g.path[k] == g.tasks[from].Name && g.path[k+1] == g.tasks[to].Name {
+			return true
+		}
+	}
+	return false
+}
+
+func statusName(status api.PlayTaskStatus) string {
+	switch status {
+	case api.PlayTaskStatusNone:
+		return "none"
+	case api.PlayTaskStatusCreated:
+		return "created"
+	case api.PlayTaskStatusBlocked:
+		return "blocked"
+	case api.PlayTaskStatusRunning:
+		return "running"
+	case api.PlayTaskStatusFailed:
+		return "failed"
+	case api.PlayTaskStatusCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+// statusColors are the fill colors of the task nodes by status.
+var statusColors = map[api.PlayTaskStatus]string{
+	api.PlayTaskStatusNone:      "#e0e0e0",
+	api.PlayTaskStatusCreated:   "#e0e0e0",
+	api.PlayTaskStatusBlocked:   "#fff3b0",
+	api.PlayTaskStatusRunning:   "#b3d9ff",
+	api.PlayTaskStatusFailed:    "#ffb3b3",
+	api.PlayTaskStatusCompleted: "#b8e6b8",
+}
+
+const (
+	defaultColor  = "#ffffff"
+	criticalColor = "#e8590c"
+)
+
+func (g *Graph) fillColor(task Task) string {
+	if !task.HasStatus {
+		return defaultColor
+	}
+	if color, ok := statusColors[task.Status]; ok {
+		return color
+	}
+	return defaultColor
+}
+
+// details is the extra info shown next to the task name.
+func details(task Task) []string {
+	var parts []string
+	if task.Machine != "" {
+		parts = append(parts, task.Machine)
+	}
+	if task.HasStatus {
+		parts = append(parts, statusName(task.Status))
+	}
+	if task.Duration > 0 {
+		parts = append(parts, formatDuration(task.Duration))
+	}
+	return parts
+}
+
+func formatDuration(d time.Duration) string {
+	if d >= time.Second {
+		d = d.Round(100 * time.Millisecond)
+	}
+	return d.String()
+}
+
+// DOT renders the graph in the Graphviz DOT language (e.g., for 'dot -Tsvg').
+func (g *Graph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph tasks {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, i := range g.order {
+		task := g.tasks[i]
+
+		label := task.Name
+		if parts := details(task); len(parts) > 0 {
+			label += "\n" + strings.Join(parts, ", ")
+		}
+
+		attrs := []string{
+			"label=" + dotQuote(label),
+			"fillcolor=" + dotQuote(g.fillColor(task)),
+		}
+		if g.critical[task.Name] {
+			attrs = append(attrs, "color="+dotQuote(criticalColor), "penwidth=3")
+		}
+
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(task.Name), strings.Join(attrs, ", "))
+	}
+
+	for _, i := range g.order {
+		for _, j := range g.needs(i) {
+			attrs := ""
+			if g.isCriticalEdge(j, i) {
+				attrs = fmt.Sprintf(" [color=%s, penwidth=3]", dotQuote(criticalColor))
+			}
+			fmt.Fprintf(&b, "  %s -> %s%s;\n", dotQuote(g.tasks[j].Name), dotQuote(g.tasks[i].Name), attrs)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// Mermaid renders the graph as a Mermaid flowchart (e.g., for a Markdown
+// code block).
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	id := func(i int) string { return fmt.Sprintf("t%d", i) }
+
+	for _, i := range g.order {
+		task := g.tasks[i]
+
+		label := mermaidEscape(task.Name)
+		if parts := details(task); len(parts) > 0 {
+			label += "<br/>" + mermaidEscape(strings.Join(parts, ", "))
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id(i), label)
+	}
+
+	var critical []int
+	edge := 0
+	for _, i := range g.order {
+		for _, j := range g.needs(i) {
+			fmt.Fprintf(&b, "  %s --> %s\n", id(j), id(i))
+			if g.isCriticalEdge(j, i) {
+				critical = append(critical, edge)
+			}
+			edge++
+		}
+	}
+
+	for _, i := range g.order {
+		task := g.tasks[i]
+
+		style := "fill:" + g.fillColor(task)
+		if g.critical[task.Name] {
+			style += ",stroke:" + criticalColor + ",stroke-width:3px"
+		}
+		fmt.Fprintf(&b, "  style %s %s\n", id(i), style)
+	}
+
+	if len(critical) > 0 {
+		var indices []string
+		for _, e := range critical {
+			indices = append(indices, fmt.Sprint(e))
+		}
+		fmt.Fprintf(&b, "  linkStyle %s stroke:%s,stroke-width:3px\n", strings.Join(indices, ","), criticalColor)
+	}
+
+	return b.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+var (
+	asciiStageStyle    = lipgloss.NewStyle().Bold(true)
+	asciiCriticalStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true)
+	asciiStatusStyles  = map[api.PlayTaskStatus]lipgloss.Style{
+		api.PlayTaskStatusBlocked:   lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		api.PlayTaskStatusRunning:   lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		api.PlayTaskStatusFailed:    lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		api.PlayTaskStatusCompleted: lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+	}
+)
+
+// ASCII renders the graph as plain text: the tasks grouped into stages (a
+// task's stage is right after the last of its needs), with the critical path
+// marked with '*' and summarized at the end.
+func (g *Graph) ASCII(color bool) string {
+	render := func(style lipgloss.Style, s string) string {
+		if !color {
+			return s
+		}
+		return style.Render(s)
+	}
+
+	var b strings.Builder
+
+	maxLevel := -1
+	for _, level := range g.levels {
+		maxLevel = max(maxLevel, level)
+	}
+
+	for level := 0; level <= maxLevel; level++ {
+		if level > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(render(asciiStageStyle, fmt.Sprintf("Stage %d", level+1)) + "\n")
+
+		for _, i := range g.order {
+			if g.levels[i] != level {
+				continue
+			}
+			task := g.tasks[i]
+
+			mark := "-"
+			if g.critical[task.Name] {
+				mark = render(asciiCriticalStyle, "*")
+			}
+
+			line := fmt.Sprintf("  %s %s", mark, task.Name)
+			if parts := details(task); len(parts) > 0 {
+				info := "(" + strings.Join(parts, ", ") + ")"
+				if style, ok := asciiStatusStyles[task.Status]; ok && task.HasStatus {
+					info = render(style, info)
+				}
+				line += " " + info
+			}
+
+			var needs []string
+			for _, j := range g.needs(i) {
+				needs = append(needs, g.tasks[j].Name)
+			}
+			if len(needs) > 0 {
+				line += " <- " + strings.Join(needs, ", ")
+			}
+
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if path, total := g.CriticalPath(); len(path) > 0 {
+		b.WriteString("\n")
+		b.WriteString(render(asciiCriticalStyle, fmt.Sprintf("Critical path (%s)", formatDuration(total))))
+		b.WriteString(": " + strings.Join(path, " -> ") + "\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package taskgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iximiuz/labctl/api"
+)
+
+func testGraph(t *testing.T) *Graph {
+	g, err := FromPlayTasks(map[string]api.PlayTaskDetails{
+		"install": {Machine: "cplane-01", Status: api.PlayTaskStatusCompleted, LastDurationMs: 20_000},
+		"images":  {Machine: "node-01", Status: api.PlayTaskStatusCompleted, LastDurationMs: 40_000},
+		"kubeadm": {Machine: "cplane-01", Status: api.PlayTaskStatusCompleted, LastDurationMs: 60_000, Needs: []string{"install"}},
+		"join":    {Machine: "node-01", Status: api.PlayTaskStatusRunning, LastDurationMs: 30_000, Needs: []string{"kubeadm", "images"}},
+		"cni":     {Machine: "cplane-01", Status: api.PlayTaskStatusBlocked, Needs: []string{"kubeadm", "unknown"}},
+	})
+	require.NoError(t, err)
+	return g
+}
+
+func TestCriticalPath(t *testing.T) {
+	path, total := testGraph(t).CriticalPath()
+	assert.Equal(t, []string{"install", "kubeadm", "join"}, path)
+	assert.Equal(t, 110*time.Second, total)
+
+	// No durations - no critical path.
+	g, err := FromManifest(map[string]api.InitTask{
+		"a": {},
+		"b": {Needs: []string{"a"}},
+	})
+	require.NoError(t, err)
+	path, _ = g.CriticalPath()
+	assert.Empty(t, path)
+}
+
+func TestCycle(t *testing.T) {
+	_, err := FromManifest(map[string]api.InitTask{
+		"a": {Needs: []string{"c"}},
+		"b": {Needs: []string{"a"}},
+		"c": {Needs: []string{"b"}},
+		"d": {},
+	})
+	assert.EqualError(t, err, "tasks a, b, c form a dependency cycle")
+}
+
+func TestASCII(t *testing.T) {
+	assert.Equal(t, `Stage 1
+  - images (node-01, completed, 40s)
+  * install (cplane-01, completed, 20s)
+
+Stage 2
+  * kubeadm (cplane-01, completed, 1m0s) <- install
+
+Stage 3
+  - cni (cplane-01, blocked) <- kubeadm
+  * join (node-01, running, 30s) <- kubeadm, images
+
+Critical path (1m50s): install -> kubeadm -> join
+`, testGraph(t).ASCII(false))
+}
+
+func TestDOT(t *testing.T) {
+	dot := testGraph(t).DOT()
+
+	assert.Contains(t, dot, `"install" [label="install\ncplane-01, completed, 20s", fillcolor="#b8e6b8", color="#e8590c", penwidth=3];`)
+	assert.Contains(t, dot, `"cni" [label="cni\ncplane-01, blocked", fillcolor="#fff3b0"];`)
+	assert.Contains(t, dot, `"install" -> "kubeadm" [color="#e8590c", penwidth=3];`)
+	assert.Contains(t, dot, `"images" -> "join";`)
+	assert.NotContains(t, dot, "unknown")
+}
+
+func TestMermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+  t1["images<br/>node-01, completed, 40s"]
+  t2["install<br/>cplane-01, completed, 20s"]
+  t4["kubeadm<br/>cplane-01, completed, 1m0s"]
+  t0["cni<br/>cplane-01, blocked"]
+  t3["join<br/>node-01, running, 30s"]
+  t2 --> t4
+  t4 --> t0
+  t4 --> t3
+  t1 --> t3
+  style t1 fill:#b8e6b8
+  style t2 fill:#b8e6b8,stroke:#e8590c,stroke-width:3px
+  style t4 fill:#b8e6b8,stroke:#e8590c,stroke-width:3px
+  style t0 fill:#fff3b0
+  style t3 fill:#b3d9ff,stroke:#e8590c,stroke-width:3px
+  linkStyle 0,2 stroke:#e8590c,stroke-width:3px
+`, testGraph(t).Mermaid())
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/gorilla/websocket"
 	"github.com/moby/term"
 	"github.com/spf13/cobra"
 
@@ -13,9 +15,12 @@ import (
 	apicmd "github.com/iximiuz/labctl/cmd/api"
 	"github.com/iximiuz/labctl/cmd/auth"
 	"github.com/iximiuz/labctl/cmd/challenge"
+	configcmd "github.com/iximiuz/labctl/cmd/config"
 	"github.com/iximiuz/labctl/cmd/content"
 	"github.com/iximiuz/labctl/cmd/course"
 	"github.com/iximiuz/labctl/cmd/cp"
+	"github.com/iximiuz/labctl/cmd/dashboard"
+	"github.com/iximiuz/labctl/cmd/down"
 	"github.com/iximiuz/labctl/cmd/expose"
 	"github.com/iximiuz/labctl/cmd/ide"
 	"github.com/iximiuz/labctl/cmd/kubeproxy"
@@ -25,8 +30,12 @@ import (
 	"github.com/iximiuz/labctl/cmd/ssh"
 	"github.com/iximiuz/labctl/cmd/sshproxy"
 	"github.com/iximiuz/labctl/cmd/tutorial"
+	"github.com/iximiuz/labctl/cmd/up"
+	"github.com/iximiuz/labctl/cmd/usage"
 	versioncmd "github.com/iximiuz/labctl/cmd/version"
 	"github.com/iximiuz/labctl/internal/config"
+	"github.com/iximiuz/labctl/internal/credentials"
+	"github.com/iximiuz/labctl/internal/har"
 	"github.com/iximiuz/labctl/internal/labcli"
 )
 
@@ -37,9 +46,14 @@ var (
 )
 
 type configOverrides struct {
+	context  string
 	endpoint string
 }
 
+type traceOptions struct {
+	harFile string
+}
+
 func main() {
 	stdin, stdout, stderr := term.StdStreams()
 	cli := labcli.NewCLI(
@@ -50,6 +64,9 @@ func main() {
 	var (
 		logLevel  string
 		overrides configOverrides
+		trace     traceOptions
+		noCache   bool
+		recorder  *har.Recorder
 	)
 
 	cmd := &cobra.Command{
@@ -61,15 +78,55 @@ func main() {
 			cmd.SilenceUsage = true
 			cmd.SilenceErrors = true
 
-			loadConfigOrFail(cli, overrides)
+			// Shell completion runs on every TAB - it must never stop to
+			// ask for the credentials passphrase.
+			completing := cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+
+			loadConfigOrFail(cli, overrides, !completing)
+
+			// The auth and config commands read and save the session
+			// directly. The rest get it on the first API request, so that
+			// version, help, etc. don't need to unlock the credentials store.
+			if !completing && needsCredentials(cmd) {
+				loadCredentials(cli)
+			}
+
+			cacheDir := cli.Config().CacheDir()
+			if noCache {
+				cacheDir = ""
+			}
+
+			var tracer api.Tracer
+			if recorder = newRecorderOrFail(cli, trace); recorder != nil {
+				tracer = recorder
+			}
 
-			cli.SetClient(api.NewClient(api.ClientOptions{
-				BaseURL:     cli.Config().BaseURL,
-				APIBaseURL:  cli.Config().APIBaseURL,
-				SessionID:   cli.Config().SessionID,
-				AccessToken: cli.Config().AccessToken,
-				UserAgent:   fmt.Sprintf("labctl/%s", cli.Version()),
-			}))
+			transport := cli.Config().Transport
+			client := api.NewClient(api.ClientOptions{
+				BaseURL:    cli.Config().BaseURL,
+				APIBaseURL: cli.Config().APIBaseURL,
+				Credentials: func() (string, string) {
+					loadCredentials(cli)
+					return cli.Config().SessionID, cli.Config().AccessToken
+				},
+				UserAgent: fmt.Sprintf("labctl/%s", cli.Version()),
+				Transport: api.TransportOptions{
+					CAFile:              transport.CAFile,
+					ProxyURL:            transport.ProxyURL,
+					ClientCertFile:      transport.ClientCertFile,
+					ClientKeyFile:       transport.ClientKeyFile,
+					DialTimeout:         transport.DialTimeout,
+					TLSHandshakeTimeout: transport.TLSHandshakeTimeout,
+				},
+				CacheDir: cacheDir,
+				Tracer:   tracer,
+			})
+
+			// wsmux (behind ssh, port-forward, ide, etc.) takes no dialer
+			// and uses gorilla's default one, so that's what gets configured.
+			websocket.DefaultDialer = client.WebSocketDialer(websocket.DefaultDialer)
+
+			cli.SetClient(client)
 		},
 	}
 	cmd.SetOut(cli.OutputStream())
@@ -79,9 +136,12 @@ func main() {
 		apicmd.NewCommand(cli),
 		auth.NewCommand(cli),
 		challenge.NewCommand(cli),
+		configcmd.NewCommand(cli),
 		content.NewCommand(cli),
 		course.NewCommand(cli),
 		cp.NewCommand(cli),
+		dashboard.NewCommand(cli),
+		down.NewCommand(cli),
 		expose.NewCommand(cli),
 		ide.NewCommand(cli),
 		kubeproxy.NewCommand(cli),
@@ -91,6 +151,8 @@ func main() {
 		ssh.NewCommand(cli),
 		sshproxy.NewCommand(cli),
 		tutorial.NewCommand(cli),
+		up.NewCommand(cli),
+		usage.NewCommand(cli),
 		versioncmd.NewCommand(cli),
 	)
 
@@ -104,14 +166,42 @@ func main() {
 		"info",
 		`log level for labctl ("debug" | "info" | "warn" | "error" | "fatal")`,
 	)
+	flags.StringVar(
+		&overrides.context,
+		"context",
+		"",
+		"Name of the config context to use for this command (see 'labctl config get-contexts')",
+	)
 	flags.StringVar(
 		&overrides.endpoint,
 		"endpoint",
 		"",
 		"iximiuz Labs API endpoint URL",
 	)
+	flags.BoolVar(
+		&noCache,
+		"no-cache",
+		false,
+		`Don't use (or update) the local cache of playground, challenge, and tutorial listings`,
+	)
+	flags.StringVar(
+		&trace.harFile,
+		"trace-http",
+		"",
+		`Record HTTP and WebSocket traffic to a HAR file (credentials are redacted)`,
+	)
+
+	err := cmd.Execute()
+
+	// The trace is flushed periodically, but the last entries are only
+	// written here.
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			cli.PrintErr("Unable to save HTTP trace: %s\n", err)
+		}
+	}
 
-	if err := cmd.Execute(); err != nil {
+	if err != nil {
 		if sterr := (labcli.StatusError{}); errors.As(err, &sterr) {
 			cli.PrintErr("labctl: %s\n", err.Error())
 			os.Exit(sterr.Code())
@@ -123,7 +213,7 @@ func main() {
 	}
 }
 
-func loadConfigOrFail(cli labcli.CLI, overrides configOverrides) {
+func loadConfigOrFail(cli labcli.CLI, overrides configOverrides, interactive bool) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		cli.PrintErr("Unable to determine home directory: %s\n", err)
@@ -133,19 +223,84 @@ func loadConfigOrFail(cli labcli.CLI, overrides configOverrides) {
 	cfg, err := config.Load(homeDir)
 	if err != nil {
 		cli.PrintErr("Unable to load config: %s\n", err)
-		cli.PrintErr("Is %s corrupted?\n", config.ConfigFilePath(homeDir))
+		cli.PrintErr("Is %s corrupted? Run 'labctl config validate' for details.\n", config.ConfigFilePath(homeDir))
 		cli.PrintErr("Using default config...\n")
 		cfg = config.Default(homeDir)
 	}
 
+	if wd, err := os.Getwd(); err == nil {
+		project, err := config.FindProject(wd)
+		if err != nil {
+			cli.PrintErr("Unable to load project config: %s\n", err)
+		}
+		cfg.Project = project
+	}
+
+	if overrides.context == "" {
+		overrides.context = cfg.Project.Context
+	}
+
+	if overrides.context != "" {
+		if err := cfg.ActivateContext(overrides.context); err != nil {
+			cli.PrintErr("Unable to switch context: %s\n", err)
+			cli.PrintErr("Available contexts: %s\n", strings.Join(cfg.ContextNames(), ", "))
+			os.Exit(1)
+		}
+	}
+
+	cfg.SetPassphrasePrompt(func() (string, error) {
+		if !interactive || !cli.InputStream().IsTerminal() {
+			return "", fmt.Errorf("not a terminal (set %s instead)", credentials.PassphraseEnvVar)
+		}
+
+		var passphrase string
+		err := cli.Password("Passphrase of the labctl credentials file", &passphrase)
+		return passphrase, err
+	})
+
 	if overrides.endpoint != "" {
-		cfg.BaseURL = overrides.endpoint
-		cfg.APIBaseURL = overrides.endpoint + "/api"
+		cfg.OverrideEndpoint(overrides.endpoint)
 	}
 
 	cli.SetConfig(cfg)
 }
 
+// loadCredentials puts the session in effect, once per run.
+func loadCredentials(cli labcli.CLI) {
+	if cli.Config().CredentialsLoaded() {
+		return
+	}
+
+	if err := cli.Config().LoadCredentials(); err != nil {
+		cli.PrintErr("Unable to load credentials: %s\n", err)
+	}
+}
+
+// needsCredentials tells whether the command is in the auth or config
+// command group.
+func needsCredentials(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if !cmd.Parent().HasParent() {
+			return cmd.Name() == "auth" || cmd.Name() == "config"
+		}
+	}
+	return false
+}
+
+func newRecorderOrFail(cli labcli.CLI, opts traceOptions) *har.Recorder {
+	if opts.harFile == "" {
+		return nil
+	}
+
+	recorder, err := har.NewRecorder(opts.harFile, cli.Version())
+	if err != nil {
+		cli.PrintErr("Unable to start HTTP trace: %s\n", err)
+		os.Exit(1)
+	}
+
+	return recorder
+}
+
 func setLogLevel(cli labcli.CLI, logLevel string) {
 	var level slog.Level
 	if err := level.UnmarshalText([]byte(logLevel)); err != nil {